@@ -0,0 +1,134 @@
+// Package jwk implements the JSON Web Key and JWK Set wire formats from
+// RFC 7517, covering the oct, RSA, EC, and OKP key types.
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+)
+
+// Key is a single JSON Web Key.
+type Key struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+
+	// oct
+	K string `json:"k,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC / OKP
+	X string `json:"x,omitempty"`
+	Y string `json:"y,omitempty"`
+}
+
+// Set is a JWK Set, the RFC 7517 wire format for a collection of keys.
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+var (
+	errUnsupportedKty = errors.New("unsupported jwk kty")
+	errUnsupportedCrv = errors.New("unsupported jwk crv")
+)
+
+// KeyByID returns the key in the set whose kid matches, if any.
+func (s *Set) KeyByID(kid string) (*Key, bool) {
+	for i := range s.Keys {
+		if s.Keys[i].Kid == kid {
+			return &s.Keys[i], true
+		}
+	}
+
+	return nil, false
+}
+
+func decodeB64(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(value)
+}
+
+// Material returns the concrete crypto key this JWK represents: []byte for
+// "oct", *rsa.PublicKey for "RSA", *ecdsa.PublicKey for "EC", and
+// ed25519.PublicKey for "OKP". It returns only public-key material;
+// private-key JWK members (d, p, q, ...) are not supported.
+func (k *Key) Material() (any, error) {
+	switch k.Kty {
+	case "oct":
+		return decodeB64(k.K)
+	case "RSA":
+		return k.rsaPublicKey()
+	case "EC":
+		return k.ecdsaPublicKey()
+	case "OKP":
+		return k.ed25519PublicKey()
+	default:
+		return nil, errUnsupportedKty
+	}
+}
+
+func (k *Key) rsaPublicKey() (*rsa.PublicKey, error) {
+	nBytes, errN := decodeB64(k.N)
+	if errN != nil {
+		return nil, errN
+	}
+	eBytes, errE := decodeB64(k.E)
+	if errE != nil {
+		return nil, errE
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
+
+func (k *Key) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	curve, errCurve := curveByName(k.Crv)
+	if errCurve != nil {
+		return nil, errCurve
+	}
+	xBytes, errX := decodeB64(k.X)
+	if errX != nil {
+		return nil, errX
+	}
+	yBytes, errY := decodeB64(k.Y)
+	if errY != nil {
+		return nil, errY
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(xBytes),
+		Y:     new(big.Int).SetBytes(yBytes),
+	}, nil
+}
+
+func (k *Key) ed25519PublicKey() (ed25519.PublicKey, error) {
+	xBytes, errX := decodeB64(k.X)
+	if errX != nil {
+		return nil, errX
+	}
+
+	return ed25519.PublicKey(xBytes), nil
+}
+
+func curveByName(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	default:
+		return nil, errUnsupportedCrv
+	}
+}
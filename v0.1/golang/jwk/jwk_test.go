@@ -0,0 +1,80 @@
+package jwk
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+)
+
+func TestKeyByID(t *testing.T) {
+	set := Set{Keys: []Key{{Kty: "oct", Kid: "kid-1"}, {Kty: "oct", Kid: "kid-2"}}}
+
+	key, found := set.KeyByID("kid-2")
+	if !found {
+		t.Fail()
+		t.Logf("expected to find kid-2")
+	}
+	if key.Kid != "kid-2" {
+		t.Fail()
+		t.Logf("expected kid-2, found %s", key.Kid)
+	}
+
+	_, found = set.KeyByID("missing")
+	if found {
+		t.Fail()
+		t.Logf("missing kid should not be found")
+	}
+}
+
+func TestMaterialOct(t *testing.T) {
+	key := Key{Kty: "oct", K: base64.RawURLEncoding.EncodeToString([]byte("super-secret"))}
+
+	material, errMaterial := key.Material()
+	if errMaterial != nil {
+		t.Fail()
+		t.Logf(errMaterial.Error())
+	}
+
+	secret, ok := material.([]byte)
+	if !ok || string(secret) != "super-secret" {
+		t.Fail()
+		t.Logf("expected decoded oct key material")
+	}
+}
+
+func TestMaterialRSA(t *testing.T) {
+	privateKey, errKey := rsa.GenerateKey(rand.Reader, 2048)
+	if errKey != nil {
+		t.Fail()
+		t.Logf(errKey.Error())
+	}
+
+	key := Key{
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(privateKey.PublicKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString([]byte{1, 0, 1}),
+	}
+
+	material, errMaterial := key.Material()
+	if errMaterial != nil {
+		t.Fail()
+		t.Logf(errMaterial.Error())
+	}
+
+	publicKey, ok := material.(*rsa.PublicKey)
+	if !ok || publicKey.E != privateKey.PublicKey.E {
+		t.Fail()
+		t.Logf("expected matching rsa public key material")
+	}
+}
+
+func TestMaterialUnsupportedKty(t *testing.T) {
+	key := Key{Kty: "unknown"}
+
+	_, errMaterial := key.Material()
+	if errMaterial != errUnsupportedKty {
+		t.Fail()
+		t.Logf("expected errUnsupportedKty")
+	}
+}
@@ -0,0 +1,195 @@
+// Package jwks fetches and caches a remote RFC 7517 JWK Set so tokens
+// signed by a rotating-key issuer (an OIDC IdP, for example) can be
+// verified without the verifier holding a long-lived secret locally.
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwts "github.com/herebythere/jwts/v0.1/golang/jwts"
+	"github.com/herebythere/jwts/v0.1/golang/jwk"
+)
+
+// defaultMinRefreshInterval is the floor on how often an unresolved kid can
+// trigger a refetch of the JWKS endpoint, regardless of Cache-Control, so a
+// burst of tokens with unknown kids cannot be used to hammer the issuer.
+const defaultMinRefreshInterval = 5 * time.Minute
+
+var (
+	errMissingKid  = errors.New("header is missing a kid")
+	errKeyNotFound = errors.New("kid not found in remote key set")
+)
+
+// Option configures a RemoteKeySet.
+type Option func(*RemoteKeySet)
+
+// WithHTTPClient overrides the http.Client used to fetch the JWKS document.
+func WithHTTPClient(client *http.Client) Option {
+	return func(ks *RemoteKeySet) {
+		ks.client = client
+	}
+}
+
+// WithMinRefreshInterval overrides the floor on refetch frequency.
+func WithMinRefreshInterval(interval time.Duration) Option {
+	return func(ks *RemoteKeySet) {
+		ks.minRefreshInterval = interval
+	}
+}
+
+// RemoteKeySet resolves verifiers from a JWKS document fetched over HTTPS,
+// caching the keys in memory and refreshing them when a kid isn't found.
+// It implements jwts.KeyResolver.
+type RemoteKeySet struct {
+	url                string
+	client             *http.Client
+	minRefreshInterval time.Duration
+
+	mu        sync.Mutex
+	set       *jwk.Set
+	fetchedAt time.Time
+	maxAge    time.Duration
+}
+
+// NewRemoteKeySet returns a resolver backed by the JWKS document at
+// jwksURL. The document is fetched lazily, on the first Resolve call.
+func NewRemoteKeySet(jwksURL string, opts ...Option) *RemoteKeySet {
+	keySet := &RemoteKeySet{
+		url:                jwksURL,
+		client:             http.DefaultClient,
+		minRefreshInterval: defaultMinRefreshInterval,
+	}
+
+	for _, opt := range opts {
+		opt(keySet)
+	}
+
+	return keySet
+}
+
+// Resolve implements jwts.KeyResolver, refreshing the cached JWKS document
+// if header.Kid isn't found and the minimum refresh interval has elapsed.
+func (ks *RemoteKeySet) Resolve(header *jwts.Header) (any, string, error) {
+	if header.Kid == "" {
+		return nil, "", errMissingKid
+	}
+
+	key, found := ks.lookup(header.Kid)
+	if !found && ks.shouldRefresh() {
+		if errRefresh := ks.Refresh(context.Background()); errRefresh != nil {
+			return nil, "", errRefresh
+		}
+		key, found = ks.lookup(header.Kid)
+	}
+	if !found {
+		return nil, "", errKeyNotFound
+	}
+
+	material, errMaterial := key.Material()
+	if errMaterial != nil {
+		return nil, "", errMaterial
+	}
+
+	alg := key.Alg
+	if alg == "" {
+		alg = header.Alg
+	}
+
+	return material, alg, nil
+}
+
+// VerifySignature fetches (if necessary) the JWKS document and verifies
+// token's signature against whichever key its kid resolves to.
+func (ks *RemoteKeySet) VerifySignature(ctx context.Context, token *string) (bool, error) {
+	if !ks.hasFetched() {
+		if errRefresh := ks.Refresh(ctx); errRefresh != nil {
+			return false, errRefresh
+		}
+	}
+
+	return jwts.ValidateTokenWithResolver(token, ks, nil)
+}
+
+// Refresh fetches the JWKS document unconditionally.
+func (ks *RemoteKeySet) Refresh(ctx context.Context) error {
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if errReq != nil {
+		return errReq
+	}
+
+	resp, errDo := ks.client.Do(req)
+	if errDo != nil {
+		return errDo
+	}
+	defer resp.Body.Close()
+
+	var set jwk.Set
+	if errDecode := json.NewDecoder(resp.Body).Decode(&set); errDecode != nil {
+		return errDecode
+	}
+
+	ks.mu.Lock()
+	ks.set = &set
+	ks.fetchedAt = time.Now()
+	ks.maxAge = parseMaxAge(resp.Header.Get("Cache-Control"))
+	ks.mu.Unlock()
+
+	return nil
+}
+
+func (ks *RemoteKeySet) lookup(kid string) (*jwk.Key, bool) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if ks.set == nil {
+		return nil, false
+	}
+
+	return ks.set.KeyByID(kid)
+}
+
+func (ks *RemoteKeySet) hasFetched() bool {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	return ks.set != nil
+}
+
+func (ks *RemoteKeySet) shouldRefresh() bool {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if ks.fetchedAt.IsZero() {
+		return true
+	}
+
+	interval := ks.minRefreshInterval
+	if ks.maxAge > interval {
+		interval = ks.maxAge
+	}
+
+	return time.Since(ks.fetchedAt) >= interval
+}
+
+func parseMaxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+
+		seconds, errParse := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if errParse == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,109 @@
+package jwks
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jwts "github.com/herebythere/jwts/v0.1/golang/jwts"
+	"github.com/herebythere/jwts/v0.1/golang/jwk"
+)
+
+const testKid = "jwks-test-kid"
+
+func newTestServer(t *testing.T, secret []byte) *httptest.Server {
+	set := jwk.Set{
+		Keys: []jwk.Key{
+			{Kty: "oct", Kid: testKid, Alg: "HS256", K: base64.RawURLEncoding.EncodeToString(secret)},
+		},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if errEncode := json.NewEncoder(w).Encode(set); errEncode != nil {
+			t.Fatalf(errEncode.Error())
+		}
+	}))
+}
+
+func TestRemoteKeySetResolve(t *testing.T) {
+	secret := []byte("remote-keyset-test-secret")
+	server := newTestServer(t, secret)
+	defer server.Close()
+
+	keySet := NewRemoteKeySet(server.URL)
+
+	material, alg, errResolve := keySet.Resolve(&jwts.Header{Alg: "HS256", Kid: testKid})
+	if errResolve != nil {
+		t.Fail()
+		t.Logf(errResolve.Error())
+	}
+	if alg != "HS256" {
+		t.Fail()
+		t.Logf("expected alg HS256, found %s", alg)
+	}
+	if string(material.([]byte)) != string(secret) {
+		t.Fail()
+		t.Logf("expected resolved secret to match")
+	}
+}
+
+func TestRemoteKeySetVerifySignature(t *testing.T) {
+	secret := []byte("remote-keyset-test-secret")
+	server := newTestServer(t, secret)
+	defer server.Close()
+
+	params := jwts.CreateTokenParams{
+		Aud:      []string{"jwks_test"},
+		Iss:      "tmk3",
+		Sub:      "test_person",
+		Lifetime: 3600,
+		KeyID:    testKid,
+	}
+	token, errToken := jwts.CreateToken(&params, jwts.HS256, secret, nil)
+	if errToken != nil {
+		t.Fail()
+		t.Logf(errToken.Error())
+	}
+
+	keySet := NewRemoteKeySet(server.URL)
+
+	tokenIsValid, errVerify := keySet.VerifySignature(context.Background(), token)
+	if !tokenIsValid {
+		t.Fail()
+		t.Logf("token should be valid")
+	}
+	if errVerify != nil {
+		t.Fail()
+		t.Logf(errVerify.Error())
+	}
+}
+
+func TestRemoteKeySetUnknownKid(t *testing.T) {
+	server := newTestServer(t, []byte("remote-keyset-test-secret"))
+	defer server.Close()
+
+	keySet := NewRemoteKeySet(server.URL)
+
+	_, _, errResolve := keySet.Resolve(&jwts.Header{Alg: "HS256", Kid: "not-present"})
+	if errResolve != errKeyNotFound {
+		t.Fail()
+		t.Logf("expected errKeyNotFound")
+	}
+}
+
+func TestParseMaxAge(t *testing.T) {
+	maxAge := parseMaxAge("public, max-age=120")
+	if maxAge.Seconds() != 120 {
+		t.Fail()
+		t.Logf("expected 120s, found %s", maxAge)
+	}
+
+	if parseMaxAge("no-store") != 0 {
+		t.Fail()
+		t.Logf("expected zero duration when max-age is absent")
+	}
+}
@@ -1,8 +1,6 @@
 package jwts
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -17,11 +15,24 @@ type CreateTokenParams struct {
 	Sub      string   `json:"sub"`
 	Lifetime int64    `json:"lifetime"`
 	Delay    *int64   `json:"delay,omitempty"`
+	KeyID    string   `json:"keyId,omitempty"`
 }
 
+// Header carries the registered JOSE header parameters from RFC 7515/7517.
+// Only Alg and Typ are required; the rest are optional key-identification
+// and certificate-chain hints used by JWK/JWKS-based verification.
 type Header struct {
-	Alg string `json:"alg"`
-	Typ string `json:"typ"`
+	Alg     string   `json:"alg"`
+	Typ     string   `json:"typ"`
+	Kid     string   `json:"kid,omitempty"`
+	Jku     string   `json:"jku,omitempty"`
+	Jwk     string   `json:"jwk,omitempty"`
+	X5c     []string `json:"x5c,omitempty"`
+	X5t     string   `json:"x5t,omitempty"`
+	X5tS256 string   `json:"x5t#S256,omitempty"`
+	X5u     string   `json:"x5u,omitempty"`
+	Cty     string   `json:"cty,omitempty"`
+	Crit    []string `json:"crit,omitempty"`
 }
 
 type Claims struct {
@@ -48,6 +59,11 @@ const (
 	dotRune = "."
 )
 
+// supportedCritParams lists the header parameters this package understands
+// when they appear in a token's "crit" header. Anything else is unsafe to
+// ignore and must fail validation.
+var supportedCritParams = map[string]bool{}
+
 var (
 	DefaultHeader = Header{
 		Alg: "HS256",
@@ -62,6 +78,8 @@ var (
 	errSecretIsNil          = errors.New("secret is nil")
 	errTokenIsNil           = errors.New("token is nil")
 	errInvalidToken         = errors.New("invalid token")
+	errResolverIsNil        = errors.New("key resolver is nil")
+	errCritParamUnsupported = errors.New("unsupported crit header parameter")
 )
 
 func encodeJSONToBase64(source interface{}) (*string, error) {
@@ -220,7 +238,8 @@ func createClaims(params *CreateTokenParams, err error) (*string, error) {
 func createSignature(
 	header *string,
 	claims *string,
-	secret *[]byte,
+	method SigningMethod,
+	key any,
 	err error,
 ) (*string, error) {
 	if err != nil {
@@ -232,14 +251,18 @@ func createSignature(
 	if claims == nil {
 		return nil, errClaimsIsNil
 	}
-	if secret == nil {
+	if method == nil {
+		return nil, errMethodIsNil
+	}
+	if key == nil {
 		return nil, errSecretIsNil
 	}
 
-	hmacSecret := hmac.New(sha256.New, *secret)
-	headerAndClaims := fmt.Sprint(*header, dotRune, *claims)
-	hmacSecret.Write([]byte(headerAndClaims))
-	signature := hmacSecret.Sum(nil)
+	signingInput := fmt.Sprint(*header, dotRune, *claims)
+	signature, errSign := method.Sign([]byte(signingInput), key)
+	if errSign != nil {
+		return nil, errSign
+	}
 	signature64 := base64.RawStdEncoding.EncodeToString(signature)
 
 	return &signature64, nil
@@ -277,48 +300,72 @@ func parseTokenDetails(
 
 func validateSignature(
 	chunks *TokenChunks,
-	secret *[]byte,
+	method SigningMethod,
+	key any,
 	err error,
 ) (
 	bool,
 	error,
 ) {
-	signatureCheck, errSignatureCheck := createSignature(
-		&chunks.Header,
-		&chunks.Claims,
-		secret,
-		err,
-	)
+	if err != nil {
+		return false, err
+	}
+	if method == nil {
+		return false, errMethodIsNil
+	}
 
-	if errSignatureCheck != nil {
-		return false, errSignatureCheck
+	signature, errDecodeSignature := base64.RawStdEncoding.DecodeString(
+		chunks.Signature,
+	)
+	if errDecodeSignature != nil {
+		return false, errDecodeSignature
 	}
 
-	signatureIsValid := chunks.Signature == *signatureCheck
-	if signatureIsValid {
-		return true, nil
+	signingInput := fmt.Sprint(chunks.Header, dotRune, chunks.Claims)
+	errVerify := method.Verify([]byte(signingInput), signature, key)
+	if errVerify != nil {
+		return false, nil
 	}
 
-	return false, nil
+	return true, nil
+}
+
+// ParseTokenDetails decodes a token's header and claims without verifying
+// its signature or window, for callers (such as jwtshttp) that need to
+// inspect claims after ValidateToken/VerifyToken have already passed.
+func ParseTokenDetails(token *string, err error) (*TokenDetails, error) {
+	chunks, errChunks := parseTokenChunks(token, err)
+
+	return parseTokenDetails(chunks, errChunks)
 }
 
 func CreateToken(
 	params *CreateTokenParams,
-	secret *[]byte,
+	method SigningMethod,
+	key any,
 	err error,
 ) (
 	*string,
 	error,
 ) {
-	if secret == nil {
+	if method == nil {
+		return nil, errMethodIsNil
+	}
+	if key == nil {
 		return nil, errSecretIsNil
 	}
 
-	claims, errClaims := createClaims(params, err)
+	header := Header{Alg: method.Alg(), Typ: "JWT"}
+	if params != nil {
+		header.Kid = params.KeyID
+	}
+	headerBase64, errHeaderBase64 := encodeJSONToBase64(&header)
+	claims, errClaims := createClaims(params, errHeaderBase64)
 	signature, errSignature := createSignature(
-		DefaultHeaderBase64,
+		headerBase64,
 		claims,
-		secret,
+		method,
+		key,
 		errClaims,
 	)
 
@@ -326,7 +373,7 @@ func CreateToken(
 		return nil, errSignature
 	}
 
-	token := fmt.Sprint(*DefaultHeaderBase64, dotRune, *claims, dotRune, *signature)
+	token := fmt.Sprint(*headerBase64, dotRune, *claims, dotRune, *signature)
 
 	return &token, nil
 }
@@ -354,15 +401,99 @@ func VerifyToken(
 	return validateTokenTimes(tokenDetails, errAudChunk)
 }
 
+// ValidateToken verifies the token's signature using method and key. The
+// header's declared "alg" must resolve to the same algorithm as method, so
+// a caller expecting HS256 can never be tricked into accepting a token
+// signed (or left unsigned) under a different algorithm.
 func ValidateToken(
 	token *string,
-	secret *[]byte,
+	method SigningMethod,
+	key any,
 	err error,
 ) (
 	bool,
 	error,
 ) {
+	if method == nil {
+		return false, errMethodIsNil
+	}
+
 	chunks, errChunks := parseTokenChunks(token, err)
+	header, errHeader := decodeFromBase64(&chunks.Header, errChunks)
+	headerDetails, errHeaderDetails := unmarshalHeader(header, errHeader)
+	if errHeaderDetails != nil {
+		return false, errHeaderDetails
+	}
+
+	resolvedMethod, errResolvedMethod := lookupSigningMethod(headerDetails.Alg)
+	if errResolvedMethod != nil {
+		return false, errResolvedMethod
+	}
+	if resolvedMethod.Alg() != method.Alg() {
+		return false, errAlgMismatch
+	}
+
+	return validateSignature(chunks, method, key, nil)
+}
+
+// KeyResolver looks up the verifier and expected algorithm for a token's
+// header, letting ValidateTokenWithResolver dispatch to different keys per
+// kid instead of requiring the caller to hold a single static key.
+type KeyResolver interface {
+	Resolve(header *Header) (verifier any, alg string, err error)
+}
+
+// checkCrit fails validation if the header names a "crit" parameter this
+// package does not understand, per RFC 7515 section 4.1.11.
+func checkCrit(header *Header) error {
+	for _, name := range header.Crit {
+		if !supportedCritParams[name] {
+			return errCritParamUnsupported
+		}
+	}
+
+	return nil
+}
+
+// ValidateTokenWithResolver verifies a token's signature using whatever key
+// resolver resolves for the token's own header, which is how kid-based
+// key rotation (StaticKeySet, and the remote JWKS resolver built on top of
+// it) is supported.
+func ValidateTokenWithResolver(
+	token *string,
+	resolver KeyResolver,
+	err error,
+) (
+	bool,
+	error,
+) {
+	if resolver == nil {
+		return false, errResolverIsNil
+	}
+
+	chunks, errChunks := parseTokenChunks(token, err)
+	header, errHeader := decodeFromBase64(&chunks.Header, errChunks)
+	headerDetails, errHeaderDetails := unmarshalHeader(header, errHeader)
+	if errHeaderDetails != nil {
+		return false, errHeaderDetails
+	}
+
+	if errCrit := checkCrit(headerDetails); errCrit != nil {
+		return false, errCrit
+	}
+
+	key, alg, errResolve := resolver.Resolve(headerDetails)
+	if errResolve != nil {
+		return false, errResolve
+	}
+
+	method, errMethod := lookupSigningMethod(alg)
+	if errMethod != nil {
+		return false, errMethod
+	}
+	if method.Alg() != headerDetails.Alg {
+		return false, errAlgMismatch
+	}
 
-	return validateSignature(chunks, secret, errChunks)
+	return validateSignature(chunks, method, key, nil)
 }
@@ -1,6 +1,11 @@
 package jwts
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"crypto/rsa"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -21,7 +26,7 @@ var (
 	testLocalSessionsBadAudChunk = "local_sessions_test_invalid_chunk"
 	headerTest64                 = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9"
 
-	jwtxParamsTest = CreateJWTParams{
+	jwtxParamsTest = CreateTokenParams{
 		Aud:      []string{testLocalSessions},
 		Iss:      tmk3,
 		Sub:      testPerson,
@@ -29,9 +34,9 @@ var (
 	}
 
 	tokenSecretTest, errTokenSecret = generateRandomByteArray(128, nil)
-	tokenTest, errTokenTest         = CreateToken(&jwtxParamsTest, tokenSecretTest, nil)
+	tokenTest, errTokenTest         = CreateToken(&jwtxParamsTest, HS256, *tokenSecretTest, nil)
 	lateDelay                       = int64(60)
-	latePayloadTest                 = CreateJWTParams{
+	latePayloadTest                 = CreateTokenParams{
 		Aud:      []string{testLocalSessions},
 		Delay:    &lateDelay,
 		Iss:      tmk3,
@@ -40,8 +45,8 @@ var (
 	}
 
 	lateTokenSecret, errLateTokenSecret = generateRandomByteArray(128, nil)
-	lateTokenTest, errLateTokenTest     = CreateToken(&latePayloadTest, lateTokenSecret, nil)
-	expiredTokenTest                    = CreateJWTParams{
+	lateTokenTest, errLateTokenTest     = CreateToken(&latePayloadTest, HS256, *lateTokenSecret, nil)
+	expiredTokenTest                    = CreateTokenParams{
 		Aud:      []string{testLocalSessions},
 		Iss:      tmk3,
 		Sub:      testPerson,
@@ -49,11 +54,11 @@ var (
 	}
 
 	expiredTokenSecret, errExpiredTokenPayloadSecret = generateRandomByteArray(128, nil)
-	expiredToken, errExpiredTokenPayload             = CreateToken(&expiredTokenTest, expiredTokenSecret, nil)
+	expiredToken, errExpiredTokenPayload             = CreateToken(&expiredTokenTest, HS256, *expiredTokenSecret, nil)
 )
 
 var (
-	testClaims = CreateJWTParams{
+	testClaims = CreateTokenParams{
 		Aud:      []string{"hello", "world"},
 		Iss:      "tmk3.com",
 		Sub:      "test_jwt",
@@ -169,7 +174,8 @@ func TestCreateSignature(t *testing.T) {
 	signature, errSignature := createSignature(
 		DefaultHeaderBase64,
 		&payload,
-		secret,
+		HS256,
+		*secret,
 		errSecret,
 	)
 
@@ -199,7 +205,7 @@ func TestCreateClaims(t *testing.T) {
 
 func TestRetrieveTokenChunks(t *testing.T) {
 	tokenSecret, errTokenSecret := generateRandomByteArray(128, nil)
-	token, errTokenPayload := CreateToken(&testClaims, tokenSecret, errTokenSecret)
+	token, errTokenPayload := CreateToken(&testClaims, HS256, *tokenSecret, errTokenSecret)
 	if token == nil {
 		t.Fail()
 		t.Logf("token should not be nil")
@@ -268,7 +274,7 @@ func TestUnmarsharClaims(t *testing.T) {
 
 func TestCreateToken(t *testing.T) {
 	secret, errTokenSecret := generateRandomByteArray(128, nil)
-	token, errTokenPayload := CreateToken(&testClaims, secret, errTokenSecret)
+	token, errTokenPayload := CreateToken(&testClaims, HS256, *secret, errTokenSecret)
 	if token == nil {
 		t.Fail()
 		t.Logf("token should not be nil")
@@ -282,7 +288,7 @@ func TestCreateToken(t *testing.T) {
 
 func TestValidateSignature(t *testing.T) {
 	tokenSecret, errTokenSecret := generateRandomByteArray(128, nil)
-	token, errTokenPayload := CreateToken(&testClaims, tokenSecret, errTokenSecret)
+	token, errTokenPayload := CreateToken(&testClaims, HS256, *tokenSecret, errTokenSecret)
 	if token == nil {
 		t.Fail()
 		t.Logf("token should not be nil")
@@ -296,7 +302,8 @@ func TestValidateSignature(t *testing.T) {
 	chunks, errChunks := parseTokenChunks(token, errTokenPayload)
 	signatureIsValid, errSignatureIsValid := validateSignature(
 		chunks,
-		tokenSecret,
+		HS256,
+		*tokenSecret,
 		errChunks,
 	)
 	if !signatureIsValid {
@@ -312,7 +319,7 @@ func TestValidateSignature(t *testing.T) {
 
 func TestParseTokenDetails(t *testing.T) {
 	tokenSecret, errTokenSecret := generateRandomByteArray(128, nil)
-	token, errTokenPayload := CreateToken(&testClaims, tokenSecret, errTokenSecret)
+	token, errTokenPayload := CreateToken(&testClaims, HS256, *tokenSecret, errTokenSecret)
 	if token == nil {
 		t.Fail()
 		t.Logf("token should not be nil")
@@ -412,7 +419,7 @@ func TestVerifyInvalidTokenWindowAndInvalidAud(t *testing.T) {
 }
 
 func TestValidateToken(t *testing.T) {
-	tokenIsValid, errTokenValid := ValidateToken(tokenTest, tokenSecretTest, nil)
+	tokenIsValid, errTokenValid := ValidateToken(tokenTest, HS256, *tokenSecretTest, nil)
 	if !tokenIsValid {
 		t.Fail()
 		t.Logf("token should be valid")
@@ -422,3 +429,100 @@ func TestValidateToken(t *testing.T) {
 		t.Logf(errTokenValid.Error())
 	}
 }
+
+func TestValidateTokenRejectsAlgMismatch(t *testing.T) {
+	tokenIsValid, errTokenValid := ValidateToken(tokenTest, HS384, *tokenSecretTest, nil)
+	if tokenIsValid {
+		t.Fail()
+		t.Logf("token signed under HS256 should not validate against HS384")
+	}
+	if errTokenValid != errAlgMismatch {
+		t.Fail()
+		t.Logf("expected errAlgMismatch")
+	}
+}
+
+func TestLookupSigningMethodRejectsNone(t *testing.T) {
+	_, errLookup := lookupSigningMethod("none")
+	if errLookup != errNoneAlgNotAllowed {
+		t.Fail()
+		t.Logf("expected errNoneAlgNotAllowed")
+	}
+}
+
+func TestCreateAndValidateTokenRSA(t *testing.T) {
+	privateKey, errKey := rsa.GenerateKey(cryptorand.Reader, 2048)
+	if errKey != nil {
+		t.Fail()
+		t.Logf(errKey.Error())
+	}
+
+	token, errToken := CreateToken(&testClaims, RS256, privateKey, nil)
+	if errToken != nil {
+		t.Fail()
+		t.Logf(errToken.Error())
+	}
+
+	tokenIsValid, errValidate := ValidateToken(token, RS256, &privateKey.PublicKey, nil)
+	if !tokenIsValid {
+		t.Fail()
+		t.Logf("token should be valid")
+	}
+	if errValidate != nil {
+		t.Fail()
+		t.Logf(errValidate.Error())
+	}
+}
+
+func TestCreateAndValidateTokenECDSA(t *testing.T) {
+	privateKey, errKey := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	if errKey != nil {
+		t.Fail()
+		t.Logf(errKey.Error())
+	}
+
+	// ECDSA signatures aren't deterministic and r/s can each be short a
+	// byte, so round-trip across enough iterations to catch a fixed-width
+	// r||s encoding bug that only shows up when r or s is short.
+	for i := 0; i < 200; i++ {
+		token, errToken := CreateToken(&testClaims, ES256, privateKey, nil)
+		if errToken != nil {
+			t.Fail()
+			t.Logf(errToken.Error())
+		}
+
+		tokenIsValid, errValidate := ValidateToken(token, ES256, &privateKey.PublicKey, nil)
+		if !tokenIsValid {
+			t.Fail()
+			t.Logf("token should be valid")
+		}
+		if errValidate != nil {
+			t.Fail()
+			t.Logf(errValidate.Error())
+		}
+	}
+}
+
+func TestCreateAndValidateTokenEd25519(t *testing.T) {
+	publicKey, privateKey, errKey := ed25519.GenerateKey(cryptorand.Reader)
+	if errKey != nil {
+		t.Fail()
+		t.Logf(errKey.Error())
+	}
+
+	token, errToken := CreateToken(&testClaims, EdDSA, privateKey, nil)
+	if errToken != nil {
+		t.Fail()
+		t.Logf(errToken.Error())
+	}
+
+	tokenIsValid, errValidate := ValidateToken(token, EdDSA, publicKey, nil)
+	if !tokenIsValid {
+		t.Fail()
+		t.Logf("token should be valid")
+	}
+	if errValidate != nil {
+		t.Fail()
+		t.Logf(errValidate.Error())
+	}
+}
@@ -0,0 +1,44 @@
+package jwts
+
+import (
+	"errors"
+
+	"github.com/herebythere/jwts/v0.1/golang/jwk"
+)
+
+var (
+	errKidRequired = errors.New("header is missing a kid")
+	errKidNotFound = errors.New("kid not found in key set")
+)
+
+// StaticKeySet resolves verifiers from an in-memory jwk.Set, dispatching on
+// the token header's kid. It implements KeyResolver.
+type StaticKeySet struct {
+	Keys *jwk.Set
+}
+
+// Resolve looks up header.Kid in the underlying jwk.Set and returns its key
+// material. The key's own "alg", when present, takes precedence over the
+// token header's "alg" so a key set entry can pin its algorithm.
+func (s *StaticKeySet) Resolve(header *Header) (any, string, error) {
+	if header.Kid == "" {
+		return nil, "", errKidRequired
+	}
+
+	key, found := s.Keys.KeyByID(header.Kid)
+	if !found {
+		return nil, "", errKidNotFound
+	}
+
+	material, errMaterial := key.Material()
+	if errMaterial != nil {
+		return nil, "", errMaterial
+	}
+
+	alg := key.Alg
+	if alg == "" {
+		alg = header.Alg
+	}
+
+	return material, alg, nil
+}
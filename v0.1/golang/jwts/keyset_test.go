@@ -0,0 +1,77 @@
+package jwts
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/herebythere/jwts/v0.1/golang/jwk"
+)
+
+func TestStaticKeySetResolve(t *testing.T) {
+	secret := []byte("keyset-test-secret")
+	keys := &jwk.Set{
+		Keys: []jwk.Key{
+			{Kty: "oct", Kid: "kid-1", Alg: "HS256", K: base64.RawURLEncoding.EncodeToString(secret)},
+		},
+	}
+	keySet := &StaticKeySet{Keys: keys}
+
+	material, alg, errResolve := keySet.Resolve(&Header{Alg: "HS256", Kid: "kid-1"})
+	if errResolve != nil {
+		t.Fail()
+		t.Logf(errResolve.Error())
+	}
+	if alg != "HS256" {
+		t.Fail()
+		t.Logf("expected alg HS256, found %s", alg)
+	}
+	if string(material.([]byte)) != string(secret) {
+		t.Fail()
+		t.Logf("expected resolved secret to match")
+	}
+}
+
+func TestStaticKeySetResolveMissingKid(t *testing.T) {
+	keySet := &StaticKeySet{Keys: &jwk.Set{}}
+
+	_, _, errResolve := keySet.Resolve(&Header{Alg: "HS256"})
+	if errResolve != errKidRequired {
+		t.Fail()
+		t.Logf("expected errKidRequired")
+	}
+}
+
+func TestValidateTokenWithResolver(t *testing.T) {
+	secret := []byte("keyset-test-secret")
+	params := CreateTokenParams{
+		Aud:      []string{"keyset_test"},
+		Iss:      "tmk3",
+		Sub:      "test_person",
+		Lifetime: 3600,
+		KeyID:    "kid-1",
+	}
+
+	token, errToken := CreateToken(&params, HS256, secret, nil)
+	if errToken != nil {
+		t.Fail()
+		t.Logf(errToken.Error())
+	}
+
+	keySet := &StaticKeySet{
+		Keys: &jwk.Set{
+			Keys: []jwk.Key{
+				{Kty: "oct", Kid: "kid-1", Alg: "HS256", K: base64.RawURLEncoding.EncodeToString(secret)},
+			},
+		},
+	}
+
+	tokenIsValid, errValidate := ValidateTokenWithResolver(token, keySet, nil)
+	if !tokenIsValid {
+		t.Fail()
+		t.Logf("token should be valid")
+	}
+	if errValidate != nil {
+		t.Fail()
+		t.Logf(errValidate.Error())
+	}
+}
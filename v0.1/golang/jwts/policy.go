@@ -0,0 +1,110 @@
+package jwts
+
+import (
+	"errors"
+	"time"
+)
+
+// ValidationPolicy configures how VerifyTokenWithPolicy checks a token's
+// claims beyond signature verification. The zero value (and a nil policy)
+// applies no leeway, requires neither nbf nor iat, and checks no maximum
+// lifetime or expected issuer/subject.
+type ValidationPolicy struct {
+	Leeway          time.Duration
+	RequireNBF      bool
+	RequireIAT      bool
+	MaxLifetime     time.Duration
+	ExpectedIssuer  string
+	ExpectedSubject string
+
+	// Now, when set, is used instead of time.Now so tests can be
+	// deterministic.
+	Now func() time.Time
+}
+
+var (
+	ErrTokenExpired     = errors.New("token is expired")
+	ErrTokenNotYetValid = errors.New("token is not yet valid")
+	ErrIssuerMismatch   = errors.New("token issuer mismatch")
+	ErrSubjectMismatch  = errors.New("token subject mismatch")
+	ErrLifetimeTooLong  = errors.New("token lifetime exceeds policy maximum")
+	ErrMissingNBF       = errors.New("token is missing a required nbf claim")
+	ErrMissingIAT       = errors.New("token is missing a required iat claim")
+	ErrAudNotFound      = errors.New("audience chunk not found in token")
+)
+
+func (p *ValidationPolicy) now() time.Time {
+	if p != nil && p.Now != nil {
+		return p.Now()
+	}
+
+	return time.Now()
+}
+
+// VerifyTokenWithPolicy checks a token's exp/nbf/iat/lifetime/iss/sub
+// claims and, if audTarget is non-nil, its aud claim, against policy.
+// Unlike VerifyToken it returns a typed error so callers can distinguish
+// "not yet valid, retry" from "reject hard".
+func VerifyTokenWithPolicy(
+	token *string,
+	policy *ValidationPolicy,
+	audTarget *string,
+) (bool, error) {
+	tokenDetails, errDetails := ParseTokenDetails(token, nil)
+	if errDetails != nil {
+		return false, errDetails
+	}
+
+	if audTarget != nil {
+		audChunkFound, _ := findAudChunk(&tokenDetails.Claims.Aud, audTarget, nil)
+		if !audChunkFound {
+			return false, ErrAudNotFound
+		}
+	}
+
+	return checkClaimsAgainstPolicy(&tokenDetails.Claims, policy)
+}
+
+func checkClaimsAgainstPolicy(claims *Claims, policy *ValidationPolicy) (bool, error) {
+	now := policy.now().Unix()
+
+	var leeway int64
+	if policy != nil {
+		leeway = int64(policy.Leeway.Seconds())
+	}
+
+	if policy != nil && policy.RequireIAT && claims.Iat == 0 {
+		return false, ErrMissingIAT
+	}
+	if claims.Iat > now+leeway {
+		return false, ErrTokenNotYetValid
+	}
+
+	if policy != nil && policy.RequireNBF && claims.Nbf == nil {
+		return false, ErrMissingNBF
+	}
+	if claims.Nbf != nil && *claims.Nbf > now+leeway {
+		return false, ErrTokenNotYetValid
+	}
+
+	if now >= claims.Exp+leeway {
+		return false, ErrTokenExpired
+	}
+
+	if policy != nil && policy.MaxLifetime > 0 {
+		lifetime := time.Duration(claims.Exp-claims.Iat) * time.Second
+		if lifetime > policy.MaxLifetime {
+			return false, ErrLifetimeTooLong
+		}
+	}
+
+	if policy != nil && policy.ExpectedIssuer != "" && claims.Iss != policy.ExpectedIssuer {
+		return false, ErrIssuerMismatch
+	}
+
+	if policy != nil && policy.ExpectedSubject != "" && claims.Sub != policy.ExpectedSubject {
+		return false, ErrSubjectMismatch
+	}
+
+	return true, nil
+}
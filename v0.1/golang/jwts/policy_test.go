@@ -0,0 +1,164 @@
+package jwts
+
+import (
+	"testing"
+	"time"
+)
+
+func newPolicyTestToken(t *testing.T, params *CreateTokenParams) *string {
+	token, errToken := CreateToken(params, HS256, []byte("policy-test-secret"), nil)
+	if errToken != nil {
+		t.Fatalf(errToken.Error())
+	}
+
+	return token
+}
+
+func TestVerifyTokenWithPolicyDefaultsAccept(t *testing.T) {
+	aud := "policy_test"
+	token := newPolicyTestToken(t, &CreateTokenParams{
+		Aud:      []string{aud},
+		Iss:      "tmk3",
+		Sub:      "test_person",
+		Lifetime: 3600,
+	})
+
+	tokenIsValid, errVerify := VerifyTokenWithPolicy(token, nil, &aud)
+	if !tokenIsValid {
+		t.Fail()
+		t.Logf("token should be valid under a nil policy")
+	}
+	if errVerify != nil {
+		t.Fail()
+		t.Logf(errVerify.Error())
+	}
+}
+
+func TestVerifyTokenWithPolicyExpired(t *testing.T) {
+	token := newPolicyTestToken(t, &CreateTokenParams{
+		Aud:      []string{"policy_test"},
+		Iss:      "tmk3",
+		Sub:      "test_person",
+		Lifetime: 0,
+	})
+
+	// Lifetime: 0 expires the instant it was issued; sleep past it.
+	time.Sleep(time.Second)
+
+	tokenIsValid, errVerify := VerifyTokenWithPolicy(token, nil, nil)
+	if tokenIsValid {
+		t.Fail()
+		t.Logf("token should be expired")
+	}
+	if errVerify != ErrTokenExpired {
+		t.Fail()
+		t.Logf("expected ErrTokenExpired")
+	}
+}
+
+func TestVerifyTokenWithPolicyLeewayAcceptsClockDrift(t *testing.T) {
+	delay := int64(2)
+	token := newPolicyTestToken(t, &CreateTokenParams{
+		Aud:      []string{"policy_test"},
+		Delay:    &delay,
+		Iss:      "tmk3",
+		Sub:      "test_person",
+		Lifetime: 3600,
+	})
+
+	policy := &ValidationPolicy{Leeway: 5 * time.Second}
+
+	tokenIsValid, errVerify := VerifyTokenWithPolicy(token, policy, nil)
+	if !tokenIsValid {
+		t.Fail()
+		t.Logf("token within leeway should be valid")
+	}
+	if errVerify != nil {
+		t.Fail()
+		t.Logf(errVerify.Error())
+	}
+}
+
+func TestVerifyTokenWithPolicyRequireNBF(t *testing.T) {
+	claims := Claims{
+		Aud: []string{"policy_test"},
+		Iat: 0,
+		Iss: "tmk3",
+		Sub: "test_person",
+		Exp: 1 << 32,
+	}
+
+	policy := &ValidationPolicy{
+		RequireNBF: true,
+		Now:        func() time.Time { return time.Unix(0, 0) },
+	}
+
+	_, errCheck := checkClaimsAgainstPolicy(&claims, policy)
+	if errCheck != ErrMissingNBF {
+		t.Fail()
+		t.Logf("expected ErrMissingNBF")
+	}
+}
+
+func TestVerifyTokenWithPolicyMaxLifetime(t *testing.T) {
+	token := newPolicyTestToken(t, &CreateTokenParams{
+		Aud:      []string{"policy_test"},
+		Iss:      "tmk3",
+		Sub:      "test_person",
+		Lifetime: 3600,
+	})
+
+	policy := &ValidationPolicy{MaxLifetime: time.Minute}
+
+	tokenIsValid, errVerify := VerifyTokenWithPolicy(token, policy, nil)
+	if tokenIsValid {
+		t.Fail()
+		t.Logf("token whose lifetime exceeds the policy maximum should be rejected")
+	}
+	if errVerify != ErrLifetimeTooLong {
+		t.Fail()
+		t.Logf("expected ErrLifetimeTooLong")
+	}
+}
+
+func TestVerifyTokenWithPolicyIssuerMismatch(t *testing.T) {
+	token := newPolicyTestToken(t, &CreateTokenParams{
+		Aud:      []string{"policy_test"},
+		Iss:      "tmk3",
+		Sub:      "test_person",
+		Lifetime: 3600,
+	})
+
+	policy := &ValidationPolicy{ExpectedIssuer: "someone-else"}
+
+	tokenIsValid, errVerify := VerifyTokenWithPolicy(token, policy, nil)
+	if tokenIsValid {
+		t.Fail()
+		t.Logf("token with mismatched issuer should be rejected")
+	}
+	if errVerify != ErrIssuerMismatch {
+		t.Fail()
+		t.Logf("expected ErrIssuerMismatch")
+	}
+}
+
+func TestVerifyTokenWithPolicyAudNotFound(t *testing.T) {
+	token := newPolicyTestToken(t, &CreateTokenParams{
+		Aud:      []string{"policy_test"},
+		Iss:      "tmk3",
+		Sub:      "test_person",
+		Lifetime: 3600,
+	})
+
+	wrongAud := "not_policy_test"
+
+	tokenIsValid, errVerify := VerifyTokenWithPolicy(token, nil, &wrongAud)
+	if tokenIsValid {
+		t.Fail()
+		t.Logf("token with mismatched aud should be rejected")
+	}
+	if errVerify != ErrAudNotFound {
+		t.Fail()
+		t.Logf("expected ErrAudNotFound")
+	}
+}
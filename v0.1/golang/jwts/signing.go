@@ -0,0 +1,243 @@
+package jwts
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"hash"
+
+	"github.com/herebythere/jwts/v0.1/golang/internal/asymcrypto"
+)
+
+// SigningMethod signs and verifies the signing input (the base64url header
+// and claims, joined by a dot) of a token. Implementations are registered
+// under their JOSE "alg" name so ValidateToken can resolve the method a
+// token's header claims to use.
+type SigningMethod interface {
+	Alg() string
+	Sign(signingInput []byte, key any) ([]byte, error)
+	Verify(signingInput []byte, signature []byte, key any) error
+}
+
+var (
+	errNoneAlgNotAllowed   = errors.New("alg \"none\" is not allowed")
+	errAlgMismatch         = errors.New("header alg does not match signing method")
+	errUnknownAlg          = errors.New("unknown signing method alg")
+	errMethodIsNil         = errors.New("signing method is nil")
+	errInvalidHMACKey      = errors.New("hmac key must be []byte")
+	errInvalidRSAKey       = errors.New("key must be *rsa.PrivateKey or *rsa.PublicKey")
+	errInvalidECDSAKey     = errors.New("key must be *ecdsa.PrivateKey or *ecdsa.PublicKey")
+	errInvalidEd25519Key   = errors.New("key must be ed25519.PrivateKey or ed25519.PublicKey")
+	errInvalidSignatureLen = errors.New("invalid signature length")
+	errSignatureMismatch   = errors.New("signature mismatch")
+)
+
+// signingMethods is the registry of algorithms known by their JOSE "alg"
+// header value.
+var signingMethods = map[string]SigningMethod{}
+
+func registerSigningMethod(method SigningMethod) {
+	signingMethods[method.Alg()] = method
+}
+
+// lookupSigningMethod resolves a SigningMethod from a JOSE "alg" header
+// value, refusing "none" outright so a stripped signature can never be
+// accepted.
+func lookupSigningMethod(alg string) (SigningMethod, error) {
+	if alg == "none" {
+		return nil, errNoneAlgNotAllowed
+	}
+
+	method, found := signingMethods[alg]
+	if !found {
+		return nil, errUnknownAlg
+	}
+
+	return method, nil
+}
+
+type hmacSigningMethod struct {
+	name string
+	hash func() hash.Hash
+}
+
+func (m *hmacSigningMethod) Alg() string {
+	return m.name
+}
+
+func (m *hmacSigningMethod) Sign(signingInput []byte, key any) ([]byte, error) {
+	secret, ok := key.([]byte)
+	if !ok {
+		return nil, errInvalidHMACKey
+	}
+
+	return asymcrypto.SignHMAC(m.hash, secret, signingInput), nil
+}
+
+func (m *hmacSigningMethod) Verify(signingInput []byte, signature []byte, key any) error {
+	secret, ok := key.([]byte)
+	if !ok {
+		return errInvalidHMACKey
+	}
+
+	if !asymcrypto.VerifyHMAC(m.hash, secret, signingInput, signature) {
+		return errSignatureMismatch
+	}
+
+	return nil
+}
+
+type rsaPKCS1SigningMethod struct {
+	name string
+	hash crypto.Hash
+}
+
+func (m *rsaPKCS1SigningMethod) Alg() string {
+	return m.name
+}
+
+func (m *rsaPKCS1SigningMethod) Sign(signingInput []byte, key any) ([]byte, error) {
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errInvalidRSAKey
+	}
+
+	return asymcrypto.SignRSAPKCS1v15(privateKey, m.hash, signingInput)
+}
+
+func (m *rsaPKCS1SigningMethod) Verify(signingInput []byte, signature []byte, key any) error {
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return errInvalidRSAKey
+	}
+
+	return asymcrypto.VerifyRSAPKCS1v15(publicKey, m.hash, signingInput, signature)
+}
+
+type rsaPSSSigningMethod struct {
+	name string
+	hash crypto.Hash
+}
+
+func (m *rsaPSSSigningMethod) Alg() string {
+	return m.name
+}
+
+func (m *rsaPSSSigningMethod) Sign(signingInput []byte, key any) ([]byte, error) {
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errInvalidRSAKey
+	}
+
+	return asymcrypto.SignRSAPSS(privateKey, m.hash, signingInput)
+}
+
+func (m *rsaPSSSigningMethod) Verify(signingInput []byte, signature []byte, key any) error {
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return errInvalidRSAKey
+	}
+
+	return asymcrypto.VerifyRSAPSS(publicKey, m.hash, signingInput, signature)
+}
+
+// ecdsaSigningMethod signs with a fixed-width, big-endian r||s encoding
+// rather than ASN.1, matching the JOSE ES256/ES384 signature format.
+type ecdsaSigningMethod struct {
+	name    string
+	hash    crypto.Hash
+	keySize int
+}
+
+func (m *ecdsaSigningMethod) Alg() string {
+	return m.name
+}
+
+func (m *ecdsaSigningMethod) Sign(signingInput []byte, key any) ([]byte, error) {
+	privateKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errInvalidECDSAKey
+	}
+
+	return asymcrypto.SignECDSA(privateKey, m.hash, m.keySize, signingInput)
+}
+
+func (m *ecdsaSigningMethod) Verify(signingInput []byte, signature []byte, key any) error {
+	publicKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return errInvalidECDSAKey
+	}
+
+	matched, errVerify := asymcrypto.VerifyECDSA(publicKey, m.hash, m.keySize, signingInput, signature)
+	if errVerify != nil {
+		return errInvalidSignatureLen
+	}
+	if !matched {
+		return errSignatureMismatch
+	}
+
+	return nil
+}
+
+type ed25519SigningMethod struct{}
+
+func (m *ed25519SigningMethod) Alg() string {
+	return "EdDSA"
+}
+
+func (m *ed25519SigningMethod) Sign(signingInput []byte, key any) ([]byte, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errInvalidEd25519Key
+	}
+
+	return asymcrypto.SignEd25519(privateKey, signingInput), nil
+}
+
+func (m *ed25519SigningMethod) Verify(signingInput []byte, signature []byte, key any) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return errInvalidEd25519Key
+	}
+
+	if !asymcrypto.VerifyEd25519(publicKey, signingInput, signature) {
+		return errSignatureMismatch
+	}
+
+	return nil
+}
+
+// Built-in signing methods, registered under their JOSE "alg" name.
+var (
+	HS256 SigningMethod = &hmacSigningMethod{name: "HS256", hash: sha256.New}
+	HS384 SigningMethod = &hmacSigningMethod{name: "HS384", hash: sha512.New384}
+	HS512 SigningMethod = &hmacSigningMethod{name: "HS512", hash: sha512.New}
+
+	RS256 SigningMethod = &rsaPKCS1SigningMethod{name: "RS256", hash: crypto.SHA256}
+	RS384 SigningMethod = &rsaPKCS1SigningMethod{name: "RS384", hash: crypto.SHA384}
+	RS512 SigningMethod = &rsaPKCS1SigningMethod{name: "RS512", hash: crypto.SHA512}
+
+	PS256 SigningMethod = &rsaPSSSigningMethod{name: "PS256", hash: crypto.SHA256}
+
+	ES256 SigningMethod = &ecdsaSigningMethod{name: "ES256", hash: crypto.SHA256, keySize: 32}
+	ES384 SigningMethod = &ecdsaSigningMethod{name: "ES384", hash: crypto.SHA384, keySize: 48}
+
+	EdDSA SigningMethod = &ed25519SigningMethod{}
+)
+
+func init() {
+	registerSigningMethod(HS256)
+	registerSigningMethod(HS384)
+	registerSigningMethod(HS512)
+	registerSigningMethod(RS256)
+	registerSigningMethod(RS384)
+	registerSigningMethod(RS512)
+	registerSigningMethod(PS256)
+	registerSigningMethod(ES256)
+	registerSigningMethod(ES384)
+	registerSigningMethod(EdDSA)
+}
@@ -0,0 +1,146 @@
+// Package jwtshttp wires the jwts package into net/http as bearer-token
+// authentication middleware.
+package jwtshttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	jwts "github.com/herebythere/jwts/v0.1/golang/jwts"
+)
+
+const bearerPrefix = "Bearer "
+
+// defaultClockSkew bounds how far a token's iat may drift from the
+// verifier's clock, matching the tolerance used by the engine-API JWT
+// handler this middleware is modeled on.
+const defaultClockSkew = 5 * time.Second
+
+// Config configures a bearer-token middleware instance.
+type Config struct {
+	Method     jwts.SigningMethod
+	Key        any
+	Audience   string
+	CookieName string
+	ClockSkew  time.Duration
+}
+
+type contextKey string
+
+const tokenDetailsKey contextKey = "jwts-token-details"
+
+var (
+	errMissingToken  = errors.New("missing bearer token")
+	errInvalidToken  = errors.New("invalid token")
+	errTokenNotReady = errors.New("token is not within its valid window")
+	errStaleIat      = errors.New("token iat is outside the allowed clock skew")
+)
+
+// NewMiddleware returns http middleware that authenticates each request's
+// bearer token against cfg, rejecting it unless the signature, the
+// exp/nbf/aud window, and the iat freshness check all pass. On success the
+// parsed *jwts.TokenDetails is injected into the request context and can be
+// read back with FromContext.
+func NewMiddleware(cfg Config) func(http.Handler) http.Handler {
+	clockSkew := cfg.ClockSkew
+	if clockSkew == 0 {
+		clockSkew = defaultClockSkew
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, errToken := extractToken(r, cfg.CookieName)
+			if errToken != nil {
+				writeUnauthorized(w)
+				return
+			}
+
+			tokenIsValid, errValidate := jwts.ValidateToken(token, cfg.Method, cfg.Key, nil)
+			if errValidate != nil || !tokenIsValid {
+				writeUnauthorized(w)
+				return
+			}
+
+			var audTarget *string
+			if cfg.Audience != "" {
+				audTarget = &cfg.Audience
+			}
+
+			tokenInWindow, errWindow := jwts.VerifyToken(token, audTarget, nil)
+			if errWindow != nil || !tokenInWindow {
+				writeForbidden(w)
+				return
+			}
+
+			tokenDetails, errDetails := jwts.ParseTokenDetails(token, nil)
+			if errDetails != nil {
+				writeForbidden(w)
+				return
+			}
+
+			if errFresh := checkIatFreshness(tokenDetails.Claims.Iat, clockSkew); errFresh != nil {
+				writeForbidden(w)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), tokenDetailsKey, tokenDetails)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// FromContext returns the *jwts.TokenDetails injected by NewMiddleware, if
+// any.
+func FromContext(ctx context.Context) (*jwts.TokenDetails, bool) {
+	tokenDetails, ok := ctx.Value(tokenDetailsKey).(*jwts.TokenDetails)
+
+	return tokenDetails, ok
+}
+
+func checkIatFreshness(iat int64, clockSkew time.Duration) error {
+	now := time.Now().Unix()
+	skew := int64(clockSkew.Seconds())
+
+	if iat > now+skew || iat < now-skew {
+		return errStaleIat
+	}
+
+	return nil
+}
+
+func extractToken(r *http.Request, cookieName string) (*string, error) {
+	authHeader := r.Header.Get("Authorization")
+	if strings.HasPrefix(authHeader, bearerPrefix) {
+		token := strings.TrimPrefix(authHeader, bearerPrefix)
+
+		return &token, nil
+	}
+
+	if cookieName != "" {
+		cookie, errCookie := r.Cookie(cookieName)
+		if errCookie == nil && cookie.Value != "" {
+			return &cookie.Value, nil
+		}
+	}
+
+	return nil, errMissingToken
+}
+
+func writeJSONError(w http.ResponseWriter, status int) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": errInvalidToken.Error()})
+}
+
+func writeUnauthorized(w http.ResponseWriter) {
+	writeJSONError(w, http.StatusUnauthorized)
+}
+
+func writeForbidden(w http.ResponseWriter) {
+	writeJSONError(w, http.StatusForbidden)
+}
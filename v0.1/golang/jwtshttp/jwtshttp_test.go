@@ -0,0 +1,107 @@
+package jwtshttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jwts "github.com/herebythere/jwts/v0.1/golang/jwts"
+)
+
+const (
+	testAudience = "jwtshttp_test"
+	testIssuer   = "tmk3"
+	testSubject  = "test_person"
+)
+
+func newTestToken(t *testing.T, secret []byte) *string {
+	params := jwts.CreateTokenParams{
+		Aud:      []string{testAudience},
+		Iss:      testIssuer,
+		Sub:      testSubject,
+		Lifetime: 3600,
+	}
+
+	token, errToken := jwts.CreateToken(&params, jwts.HS256, secret, nil)
+	if errToken != nil {
+		t.Fatalf(errToken.Error())
+	}
+
+	return token
+}
+
+func TestMiddlewareAcceptsValidToken(t *testing.T) {
+	secret := []byte("jwtshttp-test-secret")
+	token := newTestToken(t, secret)
+
+	var injectedDetails *jwts.TokenDetails
+	handler := NewMiddleware(Config{
+		Method:   jwts.HS256,
+		Key:      secret,
+		Audience: testAudience,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		injectedDetails, _ = FromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", bearerPrefix+*token)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fail()
+		t.Logf("expected 200, got %d", recorder.Code)
+	}
+
+	if injectedDetails == nil {
+		t.Fail()
+		t.Logf("token details should have been injected into the context")
+	}
+}
+
+func TestMiddlewareRejectsMissingToken(t *testing.T) {
+	handler := NewMiddleware(Config{
+		Method:   jwts.HS256,
+		Key:      []byte("jwtshttp-test-secret"),
+		Audience: testAudience,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fail()
+		t.Logf("expected 401, got %d", recorder.Code)
+	}
+
+	if recorder.Header().Get("WWW-Authenticate") == "" {
+		t.Fail()
+		t.Logf("expected a WWW-Authenticate header")
+	}
+}
+
+func TestMiddlewareRejectsWrongSecret(t *testing.T) {
+	token := newTestToken(t, []byte("jwtshttp-test-secret"))
+
+	handler := NewMiddleware(Config{
+		Method:   jwts.HS256,
+		Key:      []byte("a-different-secret"),
+		Audience: testAudience,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", bearerPrefix+*token)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fail()
+		t.Logf("expected 401, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,419 @@
+package jwtx
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/herebythere/jwts/v0.1/golang/internal/asymcrypto"
+)
+
+// Algorithm signs and verifies a token's signing input (the base64 header
+// and claims, joined by a dot). Unlike a bare secret, an Algorithm already
+// carries whatever key material it needs, so CreateJWTFromSecret and
+// ValidateJWT no longer hardcode HMAC-SHA256.
+type Algorithm interface {
+	Name() string
+	Sign(signingInput []byte) ([]byte, error)
+	Verify(signingInput []byte, signature []byte) error
+}
+
+var (
+	errNoneAlgNotAllowed   = errors.New("alg \"none\" is not allowed")
+	errAlgMismatch         = errors.New("header alg does not match verifying algorithm")
+	errAlgorithmIsNil      = errors.New("algorithm is nil")
+	errPrivateKeyRequired  = errors.New("algorithm has no private key to sign with")
+	errPublicKeyRequired   = errors.New("algorithm has no public key to verify with")
+	errSignatureMismatch   = errors.New("signature mismatch")
+	errInvalidSignatureLen = errors.New("invalid signature length")
+	errUnsupportedKty      = errors.New("unsupported jwk kty")
+	errUnsupportedCrv      = errors.New("unsupported jwk crv")
+	errUnsupportedAlg      = errors.New("unsupported jwk alg")
+	errKeyMaterialMismatch = errors.New("key material does not match alg")
+	errKeySetIsNil         = errors.New("key set is nil")
+)
+
+// KeySet resolves the key material for a kid seen in a token's header,
+// letting ValidateJWTWithKeySet verify tokens signed by a key whose id
+// rotates (for example, a remote JWKS document fetched by jwtx/jwks).
+type KeySet interface {
+	KeyByID(kid string) (Key, error)
+}
+
+// ValidateJWTWithKeySet verifies token against whichever key ks.KeyByID
+// resolves for the header's kid, rejecting alg "none" and any mismatch
+// between the header's alg and the resolved key's own alg (when the key
+// has one).
+func ValidateJWTWithKeySet(token *string, ks KeySet) (bool, error) {
+	if ks == nil {
+		return false, errKeySetIsNil
+	}
+
+	chunks, errChunks := retrieveTokenChunks(token, nil)
+	header, errHeader := decodeFromBase64(&chunks.Header, errChunks)
+	headerDetails, errHeaderDetails := unmarshalHeader(header, errHeader)
+	if errHeaderDetails != nil {
+		return false, errHeaderDetails
+	}
+
+	if headerDetails.Alg == "none" {
+		return false, errNoneAlgNotAllowed
+	}
+
+	key, errKey := ks.KeyByID(headerDetails.Kid)
+	if errKey != nil {
+		return false, errKey
+	}
+	if key.Alg != "" && key.Alg != headerDetails.Alg {
+		return false, errAlgMismatch
+	}
+
+	algorithm, errAlgorithm := algorithmFromKey(Key{Alg: headerDetails.Alg, Material: key.Material})
+	if errAlgorithm != nil {
+		return false, errAlgorithm
+	}
+
+	signatureJSON, errSignatureJSON := decodeFromBase64(&chunks.Signature, nil)
+	if errSignatureJSON != nil {
+		return false, errSignatureJSON
+	}
+
+	var signature []byte
+	if errDecodeSignature := json.Unmarshal([]byte(*signatureJSON), &signature); errDecodeSignature != nil {
+		return false, errDecodeSignature
+	}
+
+	signingInput := fmt.Sprint(chunks.Header, periodRune, chunks.Claims)
+	if errVerify := algorithm.Verify([]byte(signingInput), signature); errVerify != nil {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+type hmacAlgorithm struct {
+	name string
+	hash func() hash.Hash
+	key  []byte
+}
+
+// HS256 returns an HMAC-SHA256 Algorithm over key.
+func HS256(key []byte) Algorithm {
+	return &hmacAlgorithm{name: "HS256", hash: sha256.New, key: key}
+}
+
+// HS384 returns an HMAC-SHA384 Algorithm over key.
+func HS384(key []byte) Algorithm {
+	return &hmacAlgorithm{name: "HS384", hash: sha512.New384, key: key}
+}
+
+// HS512 returns an HMAC-SHA512 Algorithm over key.
+func HS512(key []byte) Algorithm {
+	return &hmacAlgorithm{name: "HS512", hash: sha512.New, key: key}
+}
+
+func (a *hmacAlgorithm) Name() string {
+	return a.name
+}
+
+func (a *hmacAlgorithm) Sign(signingInput []byte) ([]byte, error) {
+	return asymcrypto.SignHMAC(a.hash, a.key, signingInput), nil
+}
+
+func (a *hmacAlgorithm) Verify(signingInput []byte, signature []byte) error {
+	if !asymcrypto.VerifyHMAC(a.hash, a.key, signingInput, signature) {
+		return errSignatureMismatch
+	}
+
+	return nil
+}
+
+type rsaAlgorithm struct {
+	name       string
+	hash       crypto.Hash
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey
+}
+
+// RS256 returns an RSA-PKCS1v15-SHA256 Algorithm. Either key may be nil if
+// the caller only needs to sign or only needs to verify.
+func RS256(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) Algorithm {
+	return &rsaAlgorithm{name: "RS256", hash: crypto.SHA256, privateKey: privateKey, publicKey: publicKey}
+}
+
+// RS384 returns an RSA-PKCS1v15-SHA384 Algorithm.
+func RS384(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) Algorithm {
+	return &rsaAlgorithm{name: "RS384", hash: crypto.SHA384, privateKey: privateKey, publicKey: publicKey}
+}
+
+// RS512 returns an RSA-PKCS1v15-SHA512 Algorithm.
+func RS512(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) Algorithm {
+	return &rsaAlgorithm{name: "RS512", hash: crypto.SHA512, privateKey: privateKey, publicKey: publicKey}
+}
+
+func (a *rsaAlgorithm) Name() string {
+	return a.name
+}
+
+func (a *rsaAlgorithm) Sign(signingInput []byte) ([]byte, error) {
+	if a.privateKey == nil {
+		return nil, errPrivateKeyRequired
+	}
+
+	return asymcrypto.SignRSAPKCS1v15(a.privateKey, a.hash, signingInput)
+}
+
+func (a *rsaAlgorithm) Verify(signingInput []byte, signature []byte) error {
+	if a.publicKey == nil {
+		return errPublicKeyRequired
+	}
+
+	return asymcrypto.VerifyRSAPKCS1v15(a.publicKey, a.hash, signingInput, signature)
+}
+
+// ecdsaAlgorithm signs with a fixed-width, big-endian r||s encoding rather
+// than ASN.1, matching the JOSE ES256/ES384 signature format.
+type ecdsaAlgorithm struct {
+	name       string
+	hash       crypto.Hash
+	keySize    int
+	privateKey *ecdsa.PrivateKey
+	publicKey  *ecdsa.PublicKey
+}
+
+// ES256 returns an ECDSA-P256-SHA256 Algorithm.
+func ES256(privateKey *ecdsa.PrivateKey, publicKey *ecdsa.PublicKey) Algorithm {
+	return &ecdsaAlgorithm{name: "ES256", hash: crypto.SHA256, keySize: 32, privateKey: privateKey, publicKey: publicKey}
+}
+
+// ES384 returns an ECDSA-P384-SHA384 Algorithm.
+func ES384(privateKey *ecdsa.PrivateKey, publicKey *ecdsa.PublicKey) Algorithm {
+	return &ecdsaAlgorithm{name: "ES384", hash: crypto.SHA384, keySize: 48, privateKey: privateKey, publicKey: publicKey}
+}
+
+func (a *ecdsaAlgorithm) Name() string {
+	return a.name
+}
+
+func (a *ecdsaAlgorithm) Sign(signingInput []byte) ([]byte, error) {
+	if a.privateKey == nil {
+		return nil, errPrivateKeyRequired
+	}
+
+	return asymcrypto.SignECDSA(a.privateKey, a.hash, a.keySize, signingInput)
+}
+
+func (a *ecdsaAlgorithm) Verify(signingInput []byte, signature []byte) error {
+	if a.publicKey == nil {
+		return errPublicKeyRequired
+	}
+
+	matched, errVerify := asymcrypto.VerifyECDSA(a.publicKey, a.hash, a.keySize, signingInput, signature)
+	if errVerify != nil {
+		return errInvalidSignatureLen
+	}
+	if !matched {
+		return errSignatureMismatch
+	}
+
+	return nil
+}
+
+type ed25519Algorithm struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+// EdDSA returns an Ed25519 Algorithm.
+func EdDSA(privateKey ed25519.PrivateKey, publicKey ed25519.PublicKey) Algorithm {
+	return &ed25519Algorithm{privateKey: privateKey, publicKey: publicKey}
+}
+
+func (a *ed25519Algorithm) Name() string {
+	return "EdDSA"
+}
+
+func (a *ed25519Algorithm) Sign(signingInput []byte) ([]byte, error) {
+	if a.privateKey == nil {
+		return nil, errPrivateKeyRequired
+	}
+
+	return asymcrypto.SignEd25519(a.privateKey, signingInput), nil
+}
+
+func (a *ed25519Algorithm) Verify(signingInput []byte, signature []byte) error {
+	if a.publicKey == nil {
+		return errPublicKeyRequired
+	}
+	if !asymcrypto.VerifyEd25519(a.publicKey, signingInput, signature) {
+		return errSignatureMismatch
+	}
+
+	return nil
+}
+
+// Key is the key material ParseJWK extracts from a decoded JWK JSON
+// object, tagged with the key's own "alg" (when present) so a caller can
+// pick the matching Algorithm constructor.
+type Key struct {
+	Alg      string
+	Material any
+}
+
+func decodeJWKBase64(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(value)
+}
+
+func curveByName(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	default:
+		return nil, errUnsupportedCrv
+	}
+}
+
+// ParseJWK converts a decoded JWK JSON object into usable key material: a
+// []byte secret for "oct", *rsa.PublicKey for "RSA", *ecdsa.PublicKey for
+// "EC" (P-256/P-384), and ed25519.PublicKey for "OKP". Only public-key
+// members are read; private-key members (d, p, q, ...) are not supported.
+func ParseJWK(jwk map[string]interface{}) (Key, error) {
+	kty, _ := jwk["kty"].(string)
+	alg, _ := jwk["alg"].(string)
+
+	switch kty {
+	case "oct":
+		k, _ := jwk["k"].(string)
+		secret, errDecode := decodeJWKBase64(k)
+		if errDecode != nil {
+			return Key{}, errDecode
+		}
+
+		return Key{Alg: alg, Material: secret}, nil
+
+	case "RSA":
+		n, _ := jwk["n"].(string)
+		e, _ := jwk["e"].(string)
+
+		nBytes, errN := decodeJWKBase64(n)
+		if errN != nil {
+			return Key{}, errN
+		}
+		eBytes, errE := decodeJWKBase64(e)
+		if errE != nil {
+			return Key{}, errE
+		}
+
+		publicKey := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(nBytes),
+			E: int(new(big.Int).SetBytes(eBytes).Int64()),
+		}
+
+		return Key{Alg: alg, Material: publicKey}, nil
+
+	case "EC":
+		crv, _ := jwk["crv"].(string)
+		x, _ := jwk["x"].(string)
+		y, _ := jwk["y"].(string)
+
+		curve, errCurve := curveByName(crv)
+		if errCurve != nil {
+			return Key{}, errCurve
+		}
+		xBytes, errX := decodeJWKBase64(x)
+		if errX != nil {
+			return Key{}, errX
+		}
+		yBytes, errY := decodeJWKBase64(y)
+		if errY != nil {
+			return Key{}, errY
+		}
+
+		publicKey := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(xBytes),
+			Y:     new(big.Int).SetBytes(yBytes),
+		}
+
+		return Key{Alg: alg, Material: publicKey}, nil
+
+	case "OKP":
+		x, _ := jwk["x"].(string)
+		xBytes, errX := decodeJWKBase64(x)
+		if errX != nil {
+			return Key{}, errX
+		}
+
+		return Key{Alg: alg, Material: ed25519.PublicKey(xBytes)}, nil
+
+	default:
+		return Key{}, errUnsupportedKty
+	}
+}
+
+// algorithmFromKey builds the verify-only Algorithm that key.Alg names,
+// using key.Material as its public key. It's the bridge a KeySet uses to go
+// from a parsed JWK back to something ValidateJWT-compatible can call.
+func algorithmFromKey(key Key) (Algorithm, error) {
+	switch key.Alg {
+	case "HS256", "HS384", "HS512":
+		secret, ok := key.Material.([]byte)
+		if !ok {
+			return nil, errKeyMaterialMismatch
+		}
+		switch key.Alg {
+		case "HS384":
+			return HS384(secret), nil
+		case "HS512":
+			return HS512(secret), nil
+		default:
+			return HS256(secret), nil
+		}
+
+	case "RS256", "RS384", "RS512":
+		publicKey, ok := key.Material.(*rsa.PublicKey)
+		if !ok {
+			return nil, errKeyMaterialMismatch
+		}
+		switch key.Alg {
+		case "RS384":
+			return RS384(nil, publicKey), nil
+		case "RS512":
+			return RS512(nil, publicKey), nil
+		default:
+			return RS256(nil, publicKey), nil
+		}
+
+	case "ES256", "ES384":
+		publicKey, ok := key.Material.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, errKeyMaterialMismatch
+		}
+		if key.Alg == "ES384" {
+			return ES384(nil, publicKey), nil
+		}
+		return ES256(nil, publicKey), nil
+
+	case "EdDSA":
+		publicKey, ok := key.Material.(ed25519.PublicKey)
+		if !ok {
+			return nil, errKeyMaterialMismatch
+		}
+		return EdDSA(nil, publicKey), nil
+
+	default:
+		return nil, errUnsupportedAlg
+	}
+}
@@ -0,0 +1,212 @@
+// Package jwks fetches and caches a remote JWKS document so jwtx tokens
+// signed by a rotating-key issuer can be verified by kid without the
+// verifier holding a long-lived secret locally.
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwtx "github.com/herebythere/jwts/v0.1/golang/jwtx"
+)
+
+// defaultTTL is how long a successfully fetched JWKS document is trusted
+// when the response carries no Cache-Control max-age. It also floors how
+// often an unresolved kid can trigger a refetch of the JWKS endpoint, so a
+// burst of tokens with unknown kids cannot be used to hammer the issuer.
+const defaultTTL = 5 * time.Minute
+
+var (
+	errMissingKid  = errors.New("token header is missing a kid")
+	errKeyNotFound = errors.New("kid not found in remote key set")
+)
+
+// Option configures a RemoteKeySet.
+type Option func(*RemoteKeySet)
+
+// WithHTTPClient overrides the http.Client used to fetch the JWKS document.
+func WithHTTPClient(client *http.Client) Option {
+	return func(ks *RemoteKeySet) {
+		ks.client = client
+	}
+}
+
+// WithDefaultTTL overrides how long a fetch is trusted absent a
+// Cache-Control max-age directive on the response.
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(ks *RemoteKeySet) {
+		ks.defaultTTL = ttl
+	}
+}
+
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// RemoteKeySet resolves jwtx.Key material by kid from a JWKS document
+// fetched over HTTP, caching entries in memory and refreshing on a cache
+// miss. It implements jwtx.KeySet. Concurrent cache misses for the same
+// unseen kid are coalesced into a single in-flight fetch.
+type RemoteKeySet struct {
+	url        string
+	client     *http.Client
+	defaultTTL time.Duration
+
+	mu         sync.Mutex
+	keys       map[string]jwtx.Key
+	fetchedAt  time.Time
+	maxAge     time.Duration
+	refreshing *refreshCall
+}
+
+// NewRemoteKeySet returns a KeySet backed by the JWKS document at jwksURL.
+// The document is fetched lazily, on the first KeyByID call.
+func NewRemoteKeySet(jwksURL string, opts ...Option) *RemoteKeySet {
+	keySet := &RemoteKeySet{
+		url:        jwksURL,
+		client:     http.DefaultClient,
+		defaultTTL: defaultTTL,
+	}
+
+	for _, opt := range opts {
+		opt(keySet)
+	}
+
+	return keySet
+}
+
+// KeyByID implements jwtx.KeySet, refreshing the cached JWKS document if
+// kid isn't found and the minimum refresh interval (defaultTTL, or
+// WithDefaultTTL's override) has elapsed since the last fetch.
+func (ks *RemoteKeySet) KeyByID(kid string) (jwtx.Key, error) {
+	if kid == "" {
+		return jwtx.Key{}, errMissingKid
+	}
+
+	key, found := ks.lookup(kid)
+	if !found && ks.expired() && ks.Refresh(context.Background()) == nil {
+		key, found = ks.lookup(kid)
+	}
+	if !found {
+		return jwtx.Key{}, errKeyNotFound
+	}
+
+	return key, nil
+}
+
+// Refresh fetches the JWKS document unconditionally, coalescing concurrent
+// callers into the single fetch already in flight.
+func (ks *RemoteKeySet) Refresh(ctx context.Context) error {
+	ks.mu.Lock()
+	if call := ks.refreshing; call != nil {
+		ks.mu.Unlock()
+		<-call.done
+		return call.err
+	}
+
+	call := &refreshCall{done: make(chan struct{})}
+	ks.refreshing = call
+	ks.mu.Unlock()
+
+	errFetch := ks.fetch(ctx)
+
+	ks.mu.Lock()
+	ks.refreshing = nil
+	ks.mu.Unlock()
+
+	call.err = errFetch
+	close(call.done)
+
+	return errFetch
+}
+
+func (ks *RemoteKeySet) fetch(ctx context.Context) error {
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if errReq != nil {
+		return errReq
+	}
+
+	resp, errDo := ks.client.Do(req)
+	if errDo != nil {
+		return errDo
+	}
+	defer resp.Body.Close()
+
+	var document struct {
+		Keys []map[string]interface{} `json:"keys"`
+	}
+	if errDecode := json.NewDecoder(resp.Body).Decode(&document); errDecode != nil {
+		return errDecode
+	}
+
+	keys := make(map[string]jwtx.Key, len(document.Keys))
+	for _, raw := range document.Keys {
+		kid, _ := raw["kid"].(string)
+		if kid == "" {
+			continue
+		}
+
+		key, errParse := jwtx.ParseJWK(raw)
+		if errParse != nil {
+			continue
+		}
+
+		keys[kid] = key
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.fetchedAt = time.Now()
+	ks.maxAge = parseMaxAge(resp.Header.Get("Cache-Control"))
+	ks.mu.Unlock()
+
+	return nil
+}
+
+func (ks *RemoteKeySet) lookup(kid string) (jwtx.Key, bool) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	key, found := ks.keys[kid]
+
+	return key, found
+}
+
+func (ks *RemoteKeySet) expired() bool {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if ks.fetchedAt.IsZero() {
+		return true
+	}
+
+	ttl := ks.defaultTTL
+	if ks.maxAge > ttl {
+		ttl = ks.maxAge
+	}
+
+	return time.Since(ks.fetchedAt) >= ttl
+}
+
+func parseMaxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+
+		seconds, errParse := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if errParse == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,153 @@
+package jwks
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jwtx "github.com/herebythere/jwts/v0.1/golang/jwtx"
+)
+
+const testKid = "jwtx-jwks-test-kid"
+
+func newTestServer(t *testing.T, secret []byte) *httptest.Server {
+	document := map[string]interface{}{
+		"keys": []map[string]interface{}{
+			{
+				"kty": "oct",
+				"kid": testKid,
+				"alg": "HS256",
+				"k":   base64.RawURLEncoding.EncodeToString(secret),
+			},
+		},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if errEncode := json.NewEncoder(w).Encode(document); errEncode != nil {
+			t.Fatalf(errEncode.Error())
+		}
+	}))
+}
+
+func TestRemoteKeySetKeyByID(t *testing.T) {
+	secret := []byte("jwtx-remote-keyset-test-secret")
+	server := newTestServer(t, secret)
+	defer server.Close()
+
+	keySet := NewRemoteKeySet(server.URL)
+
+	key, errKeyByID := keySet.KeyByID(testKid)
+	if errKeyByID != nil {
+		t.Fail()
+		t.Logf(errKeyByID.Error())
+	}
+	if key.Alg != "HS256" {
+		t.Fail()
+		t.Logf("expected alg HS256, found %s", key.Alg)
+	}
+	if string(key.Material.([]byte)) != string(secret) {
+		t.Fail()
+		t.Logf("expected resolved secret to match")
+	}
+}
+
+func TestRemoteKeySetUnknownKid(t *testing.T) {
+	server := newTestServer(t, []byte("jwtx-remote-keyset-test-secret"))
+	defer server.Close()
+
+	keySet := NewRemoteKeySet(server.URL)
+
+	_, errKeyByID := keySet.KeyByID("not-present")
+	if errKeyByID != errKeyNotFound {
+		t.Fail()
+		t.Logf("expected errKeyNotFound")
+	}
+}
+
+func TestValidateJWTWithKeySet(t *testing.T) {
+	secret := []byte("jwtx-remote-keyset-test-secret")
+	server := newTestServer(t, secret)
+	defer server.Close()
+
+	params := jwtx.CreateJWTParams{
+		Aud:      []string{"jwtx_jwks_test"},
+		Iss:      "tmk3",
+		Sub:      "test_person",
+		Lifetime: 3600,
+		KeyID:    testKid,
+	}
+	tokenPayload, errToken := jwtx.CreateJWTFromSecret(&params, jwtx.HS256(secret), nil)
+	if errToken != nil {
+		t.Fail()
+		t.Logf(errToken.Error())
+	}
+
+	keySet := NewRemoteKeySet(server.URL)
+
+	tokenIsValid, errVerify := jwtx.ValidateJWTWithKeySet(tokenPayload.Token, keySet)
+	if !tokenIsValid {
+		t.Fail()
+		t.Logf("token should be valid")
+	}
+	if errVerify != nil {
+		t.Fail()
+		t.Logf(errVerify.Error())
+	}
+}
+
+func TestRemoteKeySetUnknownKidIsRateLimited(t *testing.T) {
+	secret := []byte("jwtx-remote-keyset-test-secret")
+	var fetchCount int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetchCount, 1)
+
+		document := map[string]interface{}{
+			"keys": []map[string]interface{}{
+				{
+					"kty": "oct",
+					"kid": testKid,
+					"alg": "HS256",
+					"k":   base64.RawURLEncoding.EncodeToString(secret),
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if errEncode := json.NewEncoder(w).Encode(document); errEncode != nil {
+			t.Fatalf(errEncode.Error())
+		}
+	}))
+	defer server.Close()
+
+	keySet := NewRemoteKeySet(server.URL, WithDefaultTTL(time.Hour))
+
+	for i := 0; i < 20; i++ {
+		if _, errKeyByID := keySet.KeyByID("attacker-unknown-kid"); errKeyByID != errKeyNotFound {
+			t.Fail()
+			t.Logf("expected errKeyNotFound")
+		}
+	}
+
+	if atomic.LoadInt32(&fetchCount) != 1 {
+		t.Fail()
+		t.Logf("expected a single fetch within the refresh interval, found %d", fetchCount)
+	}
+}
+
+func TestParseMaxAge(t *testing.T) {
+	maxAge := parseMaxAge("public, max-age=120")
+	if maxAge.Seconds() != 120 {
+		t.Fail()
+		t.Logf("expected 120s, found %s", maxAge)
+	}
+
+	if parseMaxAge("no-store") != 0 {
+		t.Fail()
+		t.Logf("expected zero duration when max-age is absent")
+	}
+}
@@ -0,0 +1,855 @@
+package jwtx
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Header carries the registered alg/typ/kid parameters plus any
+// unrecognized ones under PrivateParams, which MarshalJSON/UnmarshalJSON
+// merge into and split back out of the same flat JSON object.
+type Header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid,omitempty"`
+
+	PrivateParams map[string]interface{} `json:"-"`
+}
+
+var registeredHeaderParams = map[string]bool{
+	"alg": true,
+	"typ": true,
+	"kid": true,
+}
+
+func (h Header) MarshalJSON() ([]byte, error) {
+	merged := make(map[string]interface{}, len(h.PrivateParams)+3)
+	for name, value := range h.PrivateParams {
+		merged[name] = value
+	}
+
+	merged["alg"] = h.Alg
+	merged["typ"] = h.Typ
+	if h.Kid != "" {
+		merged["kid"] = h.Kid
+	}
+
+	return json.Marshal(merged)
+}
+
+func (h *Header) UnmarshalJSON(data []byte) error {
+	type registeredHeader struct {
+		Alg string `json:"alg"`
+		Typ string `json:"typ"`
+		Kid string `json:"kid,omitempty"`
+	}
+
+	var registered registeredHeader
+	if errUnmarshal := json.Unmarshal(data, &registered); errUnmarshal != nil {
+		return errUnmarshal
+	}
+
+	var raw map[string]interface{}
+	if errUnmarshal := json.Unmarshal(data, &raw); errUnmarshal != nil {
+		return errUnmarshal
+	}
+
+	h.Alg = registered.Alg
+	h.Typ = registered.Typ
+	h.Kid = registered.Kid
+	h.PrivateParams = extractPrivateParams(raw, registeredHeaderParams)
+
+	return nil
+}
+
+// Claims carries the registered RFC 7519 claims plus any application or
+// issuer-specific claims under PrivateParams, which MarshalJSON/
+// UnmarshalJSON merge into and split back out of the same flat JSON
+// object.
+type Claims struct {
+	Aud []string `json:"aud"`
+	Exp int64    `json:"exp"`
+	Iat int64    `json:"iat"`
+	Iss string   `json:"iss"`
+	Nbf *int64   `json:"nbf,omitempty"`
+	Sub string   `json:"sub"`
+
+	PrivateParams map[string]interface{} `json:"-"`
+}
+
+var registeredClaimsParams = map[string]bool{
+	"aud": true,
+	"exp": true,
+	"iat": true,
+	"iss": true,
+	"nbf": true,
+	"sub": true,
+}
+
+func (c Claims) MarshalJSON() ([]byte, error) {
+	merged := make(map[string]interface{}, len(c.PrivateParams)+6)
+	for name, value := range c.PrivateParams {
+		merged[name] = value
+	}
+
+	merged["aud"] = c.Aud
+	merged["exp"] = c.Exp
+	merged["iat"] = c.Iat
+	merged["iss"] = c.Iss
+	if c.Nbf != nil {
+		merged["nbf"] = *c.Nbf
+	}
+	merged["sub"] = c.Sub
+
+	return json.Marshal(merged)
+}
+
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	type registeredClaims struct {
+		Aud []string `json:"aud"`
+		Exp int64    `json:"exp"`
+		Iat int64    `json:"iat"`
+		Iss string   `json:"iss"`
+		Nbf *int64   `json:"nbf,omitempty"`
+		Sub string   `json:"sub"`
+	}
+
+	var registered registeredClaims
+	if errUnmarshal := json.Unmarshal(data, &registered); errUnmarshal != nil {
+		return errUnmarshal
+	}
+
+	var raw map[string]interface{}
+	if errUnmarshal := json.Unmarshal(data, &raw); errUnmarshal != nil {
+		return errUnmarshal
+	}
+
+	c.Aud = registered.Aud
+	c.Exp = registered.Exp
+	c.Iat = registered.Iat
+	c.Iss = registered.Iss
+	c.Nbf = registered.Nbf
+	c.Sub = registered.Sub
+	c.PrivateParams = extractPrivateParams(raw, registeredClaimsParams)
+
+	return nil
+}
+
+// Get returns the private claim named name, alongside whether it was
+// present.
+func (c *Claims) Get(name string) (interface{}, bool) {
+	value, found := c.PrivateParams[name]
+
+	return value, found
+}
+
+// Set adds or overwrites the private claim named name.
+func (c *Claims) Set(name string, value interface{}) {
+	if c.PrivateParams == nil {
+		c.PrivateParams = map[string]interface{}{}
+	}
+
+	c.PrivateParams[name] = value
+}
+
+func extractPrivateParams(raw map[string]interface{}, registered map[string]bool) map[string]interface{} {
+	private := make(map[string]interface{})
+	for name, value := range raw {
+		if !registered[name] {
+			private[name] = value
+		}
+	}
+
+	if len(private) == 0 {
+		return nil
+	}
+
+	return private
+}
+
+type CreateJWTParams struct {
+	Aud      []string               `json:"aud"`
+	Iss      string                 `json:"iss"`
+	Sub      string                 `json:"sub"`
+	Lifetime int64                  `json:"lifetime"`
+	Delay    *int64                 `json:"delay,omitempty"`
+	KeyID    string                 `json:"keyId,omitempty"`
+	Custom   map[string]interface{} `json:"custom,omitempty"`
+}
+
+type TokenChunks struct {
+	Header    string `json:"header"`
+	Claims    string `json:"claims"`
+	Signature string `json:"signature"`
+}
+
+type TokenPayload struct {
+	Token     *string `json:"token"`
+	Secret    *[]byte `json:"secret"`
+	Signature *string `json:"signature"`
+}
+
+type TokenDetails struct {
+	Header *Header `json:"header"`
+	Claims *Claims `json:"claims"`
+}
+
+// ValidationOptions configures the clock-skew tolerance
+// ValidateTokenByWindowAndAudWithOptions applies to iat/nbf/exp. A nil
+// *ValidationOptions, or a zero field within one, falls back to
+// defaultLeeway/defaultMaxIatAge.
+type ValidationOptions struct {
+	Leeway    time.Duration
+	MaxIatAge time.Duration
+}
+
+const (
+	periodRune   = "."
+	randomLength = 128
+
+	defaultLeeway    = 5 * time.Second
+	defaultMaxIatAge = 60 * time.Second
+)
+
+var (
+	headerDefaultParams = Header{
+		Alg: "HS256",
+		Typ: "JWT",
+	}
+	headerBase64, errHeaderBase64 = encodeToBase64(&headerDefaultParams)
+
+	errSourceIsNil             = errors.New("decoding source is nil")
+	errNilCreateParams         = errors.New("nil CreateJWTParams params")
+	errHeaderIsNil             = errors.New("header is nil")
+	errClaimsIsNil             = errors.New("claims is nil")
+	errSecretIsNil             = errors.New("secret is nil")
+	errTokenIsNil              = errors.New("token is nil")
+	errInvalidToken            = errors.New("invalid token")
+	errTokenIsExpired          = errors.New("token is expired")
+	errTokenIssuedBeforeNow    = errors.New("token is issued before now")
+	errTokenUsedBeforeExpected = errors.New("token was used before expected time")
+	errAudChunkNotFound        = errors.New("audience chunk not found in token")
+	errNilTokenDetails         = errors.New("nil token details")
+	errTokenPayloadIsNil       = errors.New("token payload is nil")
+	errTokenIatTooStale        = errors.New("token iat is too old")
+)
+
+func (o *ValidationOptions) leeway() time.Duration {
+	if o == nil {
+		return defaultLeeway
+	}
+
+	return o.Leeway
+}
+
+func (o *ValidationOptions) maxIatAge() time.Duration {
+	if o == nil {
+		return defaultMaxIatAge
+	}
+
+	return o.MaxIatAge
+}
+
+func encodeToBase64(source interface{}) (*string, error) {
+	if source == nil {
+		return nil, errSourceIsNil
+	}
+
+	marshaled, errMarshaled := json.Marshal(source)
+	if errMarshaled != nil {
+		return nil, errMarshaled
+	}
+
+	marshaled64 := base64.RawStdEncoding.EncodeToString(marshaled)
+
+	return &marshaled64, nil
+}
+
+func decodeFromBase64(source *string, err error) (*string, error) {
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, errSourceIsNil
+	}
+
+	data64, errData64 := base64.RawStdEncoding.DecodeString(*source)
+	data64AsStr := string(data64)
+
+	return &data64AsStr, errData64
+}
+
+// encodeToBase64URL is encodeToBase64 but base64url (RFC 4648 §5) encoded,
+// as RFC 7515 §2 requires for JWS segments. It backs the Strict entry
+// points rather than replacing encodeToBase64 outright, since switching
+// the existing encoding in place would silently invalidate every token
+// already issued by CreateJWT/CreateJWTFromSecret.
+func encodeToBase64URL(source interface{}) (*string, error) {
+	if source == nil {
+		return nil, errSourceIsNil
+	}
+
+	marshaled, errMarshaled := json.Marshal(source)
+	if errMarshaled != nil {
+		return nil, errMarshaled
+	}
+
+	marshaled64 := base64.RawURLEncoding.EncodeToString(marshaled)
+
+	return &marshaled64, nil
+}
+
+func decodeFromBase64URL(source *string, err error) (*string, error) {
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, errSourceIsNil
+	}
+
+	data64, errData64 := base64.RawURLEncoding.DecodeString(*source)
+	data64AsStr := string(data64)
+
+	return &data64AsStr, errData64
+}
+
+func generateRandomByteArray(n int, err error) (*[]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	token := make([]byte, n)
+	length, errRandom := rand.Read(token)
+	if errRandom != nil || length != n {
+		return nil, errRandom
+	}
+
+	return &token, nil
+}
+
+// generateSecureRandomByteArray is generateRandomByteArray but drawn from
+// crypto/rand instead of math/rand, which is predictable once its default
+// seed is known. It backs CreateJWTStrict so freshly generated HS256
+// secrets can't be guessed by an attacker who's never seen one.
+func generateSecureRandomByteArray(n int, err error) (*[]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	token := make([]byte, n)
+	length, errRandom := cryptorand.Read(token)
+	if errRandom != nil || length != n {
+		return nil, errRandom
+	}
+
+	return &token, nil
+}
+
+func getNowAsSecond() int64 {
+	return time.Now().Unix()
+}
+
+func generateSignature(
+	header *string,
+	claims *string,
+	algorithm Algorithm,
+	err error,
+) (*string, error) {
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errHeaderIsNil
+	}
+	if claims == nil {
+		return nil, errClaimsIsNil
+	}
+	if algorithm == nil {
+		return nil, errAlgorithmIsNil
+	}
+
+	signingInput := fmt.Sprint(*header, periodRune, *claims)
+	signature, errSign := algorithm.Sign([]byte(signingInput))
+	if errSign != nil {
+		return nil, errSign
+	}
+
+	return encodeToBase64(signature)
+}
+
+// generateSignatureStrict is generateSignature, but the signature chunk it
+// produces is base64url(signature) per RFC 7515 section 2, not
+// base64url(json.Marshal(signature)) — json.Marshal of a []byte already
+// base64-encodes it and wraps the result in quotes, so running that
+// through encodeToBase64URL would double-encode the signature chunk
+// instead of leaving it directly decodable by another JWS implementation.
+func generateSignatureStrict(
+	header *string,
+	claims *string,
+	algorithm Algorithm,
+	err error,
+) (*string, error) {
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errHeaderIsNil
+	}
+	if claims == nil {
+		return nil, errClaimsIsNil
+	}
+	if algorithm == nil {
+		return nil, errAlgorithmIsNil
+	}
+
+	signingInput := fmt.Sprint(*header, periodRune, *claims)
+	signature, errSign := algorithm.Sign([]byte(signingInput))
+	if errSign != nil {
+		return nil, errSign
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(signature)
+
+	return &encoded, nil
+}
+
+func buildJWTClaims(params *CreateJWTParams, err error) (*Claims, error) {
+	if err != nil {
+		return nil, err
+	}
+	if params == nil {
+		return nil, errNilCreateParams
+	}
+
+	nowAsSecond := getNowAsSecond()
+	expiration := nowAsSecond + params.Lifetime
+
+	var notBefore int64
+	if params.Delay != nil {
+		notBefore = nowAsSecond + *params.Delay
+	}
+
+	claims := Claims{
+		Aud:           params.Aud,
+		Exp:           expiration,
+		Iat:           nowAsSecond,
+		Iss:           params.Iss,
+		Nbf:           &notBefore,
+		Sub:           params.Sub,
+		PrivateParams: params.Custom,
+	}
+
+	return &claims, nil
+}
+
+func createJWTClaims(params *CreateJWTParams, err error) (*string, error) {
+	claims, errClaims := buildJWTClaims(params, err)
+	if errClaims != nil {
+		return nil, errClaims
+	}
+
+	return encodeToBase64(*claims)
+}
+
+func createJWTClaimsStrict(params *CreateJWTParams, err error) (*string, error) {
+	claims, errClaims := buildJWTClaims(params, err)
+	if errClaims != nil {
+		return nil, errClaims
+	}
+
+	return encodeToBase64URL(*claims)
+}
+
+func retrieveTokenChunks(token *string, err error) (*TokenChunks, error) {
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errTokenIsNil
+	}
+
+	chunks := strings.Split(*token, ".")
+	if len(chunks) != 3 {
+		return nil, errInvalidToken
+	}
+
+	tokenChunks := TokenChunks{
+		Header:    chunks[0],
+		Claims:    chunks[1],
+		Signature: chunks[2],
+	}
+
+	return &tokenChunks, nil
+}
+
+func unmarshalHeader(header *string, err error) (*Header, error) {
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errHeaderIsNil
+	}
+
+	var headerDetails Header
+	errHeaderMarshal := json.Unmarshal([]byte(*header), &headerDetails)
+
+	return &headerDetails, errHeaderMarshal
+}
+
+func unmarshalClaims(claims *string, err error) (*Claims, error) {
+	if err != nil {
+		return nil, err
+	}
+	if claims == nil {
+		return nil, errClaimsIsNil
+	}
+
+	var claimsDetails Claims
+	errClaimsMarshal := json.Unmarshal([]byte(*claims), &claimsDetails)
+
+	return &claimsDetails, errClaimsMarshal
+}
+
+func findAudChunk(aud *[]string, audTarget string) bool {
+	for _, audChunk := range *aud {
+		if audChunk == audTarget {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateJWT signs with a freshly generated HS256 secret, returning it in
+// TokenPayload.Secret for the caller to persist; it owns the secret's
+// entire lifecycle, so it doesn't take an Algorithm like CreateJWTFromSecret
+// and ValidateJWT do.
+func CreateJWT(params *CreateJWTParams, err error) (*TokenPayload, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	claims, errClaims := createJWTClaims(params, nil)
+	secret, errSecret := generateRandomByteArray(randomLength, errClaims)
+
+	var algorithm Algorithm
+	if secret != nil {
+		algorithm = HS256(*secret)
+	}
+
+	headerB64, errHeaderB64 := encodeToBase64(&headerDefaultParams)
+	signature, errSignature := generateSignature(headerB64, claims, algorithm, errSecret)
+	if errHeaderB64 != nil {
+		return nil, errHeaderB64
+	}
+	if errSignature != nil {
+		return nil, errSignature
+	}
+
+	token := fmt.Sprint(*headerB64, periodRune, *claims, periodRune, *signature)
+	tokenPayload := TokenPayload{
+		Token:     &token,
+		Secret:    secret,
+		Signature: signature,
+	}
+
+	return &tokenPayload, nil
+}
+
+// CreateJWTStrict is CreateJWT, but base64url-encoded per RFC 7515 §2 and
+// backed by a crypto/rand-generated secret instead of math/rand. Tokens it
+// issues aren't readable by ValidateJWT/RetrieveTokenDetails (and vice
+// versa), so callers migrate to it deliberately rather than having every
+// previously-issued token silently stop validating.
+func CreateJWTStrict(params *CreateJWTParams, err error) (*TokenPayload, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	claims, errClaims := createJWTClaimsStrict(params, nil)
+	secret, errSecret := generateSecureRandomByteArray(randomLength, errClaims)
+
+	var algorithm Algorithm
+	if secret != nil {
+		algorithm = HS256(*secret)
+	}
+
+	headerB64, errHeaderB64 := encodeToBase64URL(&headerDefaultParams)
+	signature, errSignature := generateSignatureStrict(headerB64, claims, algorithm, errSecret)
+	if errHeaderB64 != nil {
+		return nil, errHeaderB64
+	}
+	if errSignature != nil {
+		return nil, errSignature
+	}
+
+	token := fmt.Sprint(*headerB64, periodRune, *claims, periodRune, *signature)
+	tokenPayload := TokenPayload{
+		Token:     &token,
+		Secret:    secret,
+		Signature: signature,
+	}
+
+	return &tokenPayload, nil
+}
+
+// CreateJWTFromSecret signs params's claims with algorithm, whose key
+// material the caller already holds.
+func CreateJWTFromSecret(params *CreateJWTParams, algorithm Algorithm, err error) (*TokenPayload, error) {
+	if err != nil {
+		return nil, err
+	}
+	if algorithm == nil {
+		return nil, errAlgorithmIsNil
+	}
+
+	claims, errClaims := createJWTClaims(params, nil)
+	header := Header{Alg: algorithm.Name(), Typ: "JWT"}
+	if params != nil {
+		header.Kid = params.KeyID
+	}
+	headerB64, errHeaderB64 := encodeToBase64(&header)
+	signature, errSignature := generateSignature(headerB64, claims, algorithm, errClaims)
+	if errHeaderB64 != nil {
+		return nil, errHeaderB64
+	}
+	if errSignature != nil {
+		return nil, errSignature
+	}
+
+	token := fmt.Sprint(*headerB64, periodRune, *claims, periodRune, *signature)
+	tokenPayload := TokenPayload{
+		Token:     &token,
+		Signature: signature,
+	}
+
+	return &tokenPayload, nil
+}
+
+// CreateJWTFromSecretStrict is CreateJWTFromSecret, base64url-encoded per
+// RFC 7515 §2. Pair it with ValidateJWTStrict, not ValidateJWT.
+func CreateJWTFromSecretStrict(params *CreateJWTParams, algorithm Algorithm, err error) (*TokenPayload, error) {
+	if err != nil {
+		return nil, err
+	}
+	if algorithm == nil {
+		return nil, errAlgorithmIsNil
+	}
+
+	claims, errClaims := createJWTClaimsStrict(params, nil)
+	header := Header{Alg: algorithm.Name(), Typ: "JWT"}
+	if params != nil {
+		header.Kid = params.KeyID
+	}
+	headerB64, errHeaderB64 := encodeToBase64URL(&header)
+	signature, errSignature := generateSignatureStrict(headerB64, claims, algorithm, errClaims)
+	if errHeaderB64 != nil {
+		return nil, errHeaderB64
+	}
+	if errSignature != nil {
+		return nil, errSignature
+	}
+
+	token := fmt.Sprint(*headerB64, periodRune, *claims, periodRune, *signature)
+	tokenPayload := TokenPayload{
+		Token:     &token,
+		Signature: signature,
+	}
+
+	return &tokenPayload, nil
+}
+
+// ValidateJWT verifies tokenPayload's signature using algorithm, rejecting
+// a header alg of "none" as well as any mismatch against algorithm.Name()
+// so a caller expecting HS256 can't be tricked into accepting a token
+// signed (or left unsigned) under a different algorithm.
+func ValidateJWT(tokenPayload *TokenPayload, algorithm Algorithm, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	if tokenPayload == nil {
+		return false, errTokenPayloadIsNil
+	}
+	if algorithm == nil {
+		return false, errAlgorithmIsNil
+	}
+
+	chunks, errChunks := retrieveTokenChunks(tokenPayload.Token, nil)
+	header, errHeader := decodeFromBase64(&chunks.Header, errChunks)
+	headerDetails, errHeaderDetails := unmarshalHeader(header, errHeader)
+	if errHeaderDetails != nil {
+		return false, errHeaderDetails
+	}
+
+	if headerDetails.Alg == "none" {
+		return false, errNoneAlgNotAllowed
+	}
+	if headerDetails.Alg != algorithm.Name() {
+		return false, errAlgMismatch
+	}
+
+	signatureJSON, errSignatureJSON := decodeFromBase64(&chunks.Signature, nil)
+	if errSignatureJSON != nil {
+		return false, errSignatureJSON
+	}
+
+	var signature []byte
+	errDecodeSignature := json.Unmarshal([]byte(*signatureJSON), &signature)
+	if errDecodeSignature != nil {
+		return false, errDecodeSignature
+	}
+
+	signingInput := fmt.Sprint(chunks.Header, periodRune, chunks.Claims)
+	errVerify := algorithm.Verify([]byte(signingInput), signature)
+	if errVerify != nil {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// ValidateJWTStrict is ValidateJWT for a token produced by CreateJWTStrict
+// or CreateJWTFromSecretStrict (base64url-encoded segments).
+func ValidateJWTStrict(tokenPayload *TokenPayload, algorithm Algorithm, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	if tokenPayload == nil {
+		return false, errTokenPayloadIsNil
+	}
+	if algorithm == nil {
+		return false, errAlgorithmIsNil
+	}
+
+	chunks, errChunks := retrieveTokenChunks(tokenPayload.Token, nil)
+	header, errHeader := decodeFromBase64URL(&chunks.Header, errChunks)
+	headerDetails, errHeaderDetails := unmarshalHeader(header, errHeader)
+	if errHeaderDetails != nil {
+		return false, errHeaderDetails
+	}
+
+	if headerDetails.Alg == "none" {
+		return false, errNoneAlgNotAllowed
+	}
+	if headerDetails.Alg != algorithm.Name() {
+		return false, errAlgMismatch
+	}
+
+	signature, errDecodeSignature := base64.RawURLEncoding.DecodeString(chunks.Signature)
+	if errDecodeSignature != nil {
+		return false, errDecodeSignature
+	}
+
+	signingInput := fmt.Sprint(chunks.Header, periodRune, chunks.Claims)
+	errVerify := algorithm.Verify([]byte(signingInput), signature)
+	if errVerify != nil {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func RetrieveTokenDetails(token *string, err error) (*TokenDetails, error) {
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errTokenIsNil
+	}
+
+	chunks, errChunks := retrieveTokenChunks(token, nil)
+	header, errHeader := decodeFromBase64(&chunks.Header, errChunks)
+	headerDetails, errHeaderDetails := unmarshalHeader(header, errHeader)
+	claims, errClaims := decodeFromBase64(&chunks.Claims, errHeaderDetails)
+	claimsDetails, errClaimsDetails := unmarshalClaims(claims, errClaims)
+
+	tokenDetails := TokenDetails{
+		Header: headerDetails,
+		Claims: claimsDetails,
+	}
+
+	return &tokenDetails, errClaimsDetails
+}
+
+// RetrieveTokenDetailsStrict is RetrieveTokenDetails for a token produced
+// by CreateJWTStrict or CreateJWTFromSecretStrict.
+func RetrieveTokenDetailsStrict(token *string, err error) (*TokenDetails, error) {
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errTokenIsNil
+	}
+
+	chunks, errChunks := retrieveTokenChunks(token, nil)
+	header, errHeader := decodeFromBase64URL(&chunks.Header, errChunks)
+	headerDetails, errHeaderDetails := unmarshalHeader(header, errHeader)
+	claims, errClaims := decodeFromBase64URL(&chunks.Claims, errHeaderDetails)
+	claimsDetails, errClaimsDetails := unmarshalClaims(claims, errClaims)
+
+	tokenDetails := TokenDetails{
+		Header: headerDetails,
+		Claims: claimsDetails,
+	}
+
+	return &tokenDetails, errClaimsDetails
+}
+
+// ValidateTokenByWindowAndAudWithOptions is ValidateTokenByWindowAndAud with
+// configurable clock-skew tolerance: iat, nbf, and exp are all checked
+// against options.leeway() rather than zero tolerance, and an iat older
+// than options.maxIatAge() is rejected as stale even though it isn't yet
+// expired. A nil options applies defaultLeeway/defaultMaxIatAge.
+func ValidateTokenByWindowAndAudWithOptions(
+	token *string,
+	audTarget string,
+	options *ValidationOptions,
+	err error,
+) (bool, error) {
+	tokenDetails, errTokenDetails := RetrieveTokenDetails(token, err)
+	if errTokenDetails != nil {
+		return false, errTokenDetails
+	}
+	if tokenDetails == nil {
+		return false, errNilTokenDetails
+	}
+
+	// check if role exists
+	audChunkFound := findAudChunk(&tokenDetails.Claims.Aud, audTarget)
+	if !audChunkFound {
+		return false, errAudChunkNotFound
+	}
+
+	currentTime := time.Now().Unix()
+	leeway := int64(options.leeway().Seconds())
+	maxIatAge := int64(options.maxIatAge().Seconds())
+
+	if tokenDetails.Claims.Iat > currentTime+leeway {
+		return false, errTokenIssuedBeforeNow
+	}
+	if tokenDetails.Claims.Iat < currentTime-maxIatAge {
+		return false, errTokenIatTooStale
+	}
+
+	if tokenDetails.Claims.Nbf != nil && currentTime < *tokenDetails.Claims.Nbf-leeway {
+		return false, errTokenUsedBeforeExpected
+	}
+
+	if currentTime < tokenDetails.Claims.Exp+leeway {
+		return true, nil
+	}
+
+	return false, errTokenIsExpired
+}
+
+// ValidateTokenByWindowAndAud checks token's aud/iat/nbf/exp claims under
+// the default clock-skew tolerance (see ValidationOptions).
+func ValidateTokenByWindowAndAud(token *string, audTarget string, err error) (bool, error) {
+	return ValidateTokenByWindowAndAudWithOptions(token, audTarget, nil, err)
+}
@@ -2,8 +2,11 @@ package jwtx
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
 const (
@@ -189,7 +192,7 @@ func TestGenerateSignature(t *testing.T) {
 	signature, errSignature := generateSignature(
 		headerBase64,
 		&payload,
-		secret,
+		HS256(*secret),
 		errSecret,
 	)
 
@@ -309,7 +312,7 @@ func TestCreateJWTFromSecret(t *testing.T) {
 
 	tokenPayload, errTokenPayload := CreateJWTFromSecret(
 		&testClaims,
-		randomBytes,
+		HS256(*randomBytes),
 		nil,
 	)
 	if tokenPayload == nil {
@@ -337,6 +340,7 @@ func TestValidateJWT(t *testing.T) {
 
 	signatureIsValid, errSignatureIsValid := ValidateJWT(
 		tokenPayload,
+		HS256(*tokenPayload.Secret),
 		errTokenPayload,
 	)
 	if !signatureIsValid {
@@ -426,7 +430,12 @@ func TestInvalidTokenWindowAndAud(t *testing.T) {
 }
 
 func TestExpiredTokenWindowAndAud(t *testing.T) {
-	tokenIsValidWindow, errTokenPayload := ValidateTokenByWindowAndAud(expiredTokenPayload.Token, testLocalSessions, nil)
+	tokenIsValidWindow, errTokenPayload := ValidateTokenByWindowAndAudWithOptions(
+		expiredTokenPayload.Token,
+		testLocalSessions,
+		&ValidationOptions{Leeway: 0, MaxIatAge: time.Hour},
+		nil,
+	)
 	if tokenIsValidWindow {
 		t.Fail()
 		t.Logf("token window should be expired")
@@ -448,3 +457,333 @@ func TestInvalidTokenWindowAndInvalidAud(t *testing.T) {
 		t.Logf("there should be an associated error with an invalid aud chunk")
 	}
 }
+
+func TestValidateJWTRejectsAlgMismatch(t *testing.T) {
+	tokenIsValid, errTokenValid := ValidateJWT(tokenPayloadTest, HS384(*tokenPayloadTest.Secret), nil)
+	if tokenIsValid {
+		t.Fail()
+		t.Logf("token signed under HS256 should not validate against HS384")
+	}
+	if errTokenValid != errAlgMismatch {
+		t.Fail()
+		t.Logf("expected errAlgMismatch")
+	}
+}
+
+func TestValidateJWTRejectsNoneAlg(t *testing.T) {
+	noneHeader, errHeader := encodeToBase64(&Header{Alg: "none", Typ: "JWT"})
+	if errHeader != nil {
+		t.Fail()
+		t.Logf(errHeader.Error())
+	}
+
+	claims, errClaims := createJWTClaims(&jwtxParamsTest, nil)
+	if errClaims != nil {
+		t.Fail()
+		t.Logf(errClaims.Error())
+	}
+
+	noneToken := fmt.Sprint(*noneHeader, periodRune, *claims, periodRune, "")
+	noneTokenPayload := TokenPayload{Token: &noneToken}
+
+	tokenIsValid, errTokenValid := ValidateJWT(&noneTokenPayload, HS256(*tokenPayloadTest.Secret), nil)
+	if tokenIsValid {
+		t.Fail()
+		t.Logf("token with alg \"none\" should not validate")
+	}
+	if errTokenValid != errNoneAlgNotAllowed {
+		t.Fail()
+		t.Logf("expected errNoneAlgNotAllowed")
+	}
+}
+
+func TestValidateTokenByWindowAndAudLeewayAcceptsClockDrift(t *testing.T) {
+	delay := int64(2)
+	params := CreateJWTParams{
+		Aud:      []string{testLocalSessions},
+		Delay:    &delay,
+		Iss:      tmk3,
+		Sub:      testPerson,
+		Lifetime: 3600,
+	}
+	tokenPayload, errTokenPayload := CreateJWT(&params, nil)
+	if errTokenPayload != nil {
+		t.Fail()
+		t.Logf(errTokenPayload.Error())
+	}
+
+	tokenIsValidWindow, errValidate := ValidateTokenByWindowAndAudWithOptions(
+		tokenPayload.Token,
+		testLocalSessions,
+		&ValidationOptions{Leeway: 5 * time.Second},
+		nil,
+	)
+	if !tokenIsValidWindow {
+		t.Fail()
+		t.Logf("token within leeway should be valid")
+	}
+	if errValidate != nil {
+		t.Fail()
+		t.Logf(errValidate.Error())
+	}
+}
+
+func newStaleIatTokenForTest(t *testing.T) *string {
+	claims := Claims{
+		Aud: []string{testLocalSessions},
+		Exp: getNowAsSecond() + 3600,
+		Iat: getNowAsSecond() - 120,
+		Iss: tmk3,
+		Sub: testPerson,
+	}
+	claimsB64, errClaimsB64 := encodeToBase64(claims)
+	if errClaimsB64 != nil {
+		t.Fail()
+		t.Logf(errClaimsB64.Error())
+	}
+
+	signature, errSignature := generateSignature(&headerTest64, claimsB64, HS256(*tokenPayloadTest.Secret), nil)
+	if errSignature != nil {
+		t.Fail()
+		t.Logf(errSignature.Error())
+	}
+
+	token := fmt.Sprint(headerTest64, periodRune, *claimsB64, periodRune, *signature)
+
+	return &token
+}
+
+func TestValidateTokenByWindowAndAudRejectsStaleIat(t *testing.T) {
+	token := newStaleIatTokenForTest(t)
+
+	tokenIsValidWindow, errValidate := ValidateTokenByWindowAndAudWithOptions(
+		token,
+		testLocalSessions,
+		&ValidationOptions{MaxIatAge: 60 * time.Second},
+		nil,
+	)
+	if tokenIsValidWindow {
+		t.Fail()
+		t.Logf("token with an iat older than MaxIatAge should be rejected")
+	}
+	if errValidate != errTokenIatTooStale {
+		t.Fail()
+		t.Logf("expected errTokenIatTooStale")
+	}
+}
+
+func TestCreateJWTCustomClaimsRoundTrip(t *testing.T) {
+	params := CreateJWTParams{
+		Aud:      []string{testLocalSessions},
+		Iss:      tmk3,
+		Sub:      testPerson,
+		Lifetime: 3600,
+		Custom: map[string]interface{}{
+			"scope": "read:everything",
+			"roles": []interface{}{"admin"},
+		},
+	}
+	tokenPayload, errTokenPayload := CreateJWT(&params, nil)
+	if errTokenPayload != nil {
+		t.Fail()
+		t.Logf(errTokenPayload.Error())
+	}
+
+	tokenDetails, errTokenDetails := RetrieveTokenDetails(tokenPayload.Token, nil)
+	if errTokenDetails != nil {
+		t.Fail()
+		t.Logf(errTokenDetails.Error())
+	}
+
+	scope, found := tokenDetails.Claims.Get("scope")
+	if !found || scope != "read:everything" {
+		t.Fail()
+		t.Logf("expected scope to round-trip through PrivateParams")
+	}
+
+	if _, found := tokenDetails.Claims.Get("roles"); !found {
+		t.Fail()
+		t.Logf("expected roles to round-trip through PrivateParams")
+	}
+}
+
+func TestClaimsSetAndGet(t *testing.T) {
+	var claims Claims
+	claims.Set("email", "person@example.com")
+
+	value, found := claims.Get("email")
+	if !found || value != "person@example.com" {
+		t.Fail()
+		t.Logf("expected Set to be visible through Get")
+	}
+
+	if _, found := claims.Get("missing"); found {
+		t.Fail()
+		t.Logf("expected missing claim to not be found")
+	}
+}
+
+func TestHeaderPrivateParamsRoundTrip(t *testing.T) {
+	header := Header{
+		Alg:           "HS256",
+		Typ:           "JWT",
+		PrivateParams: map[string]interface{}{"cty": "JWT"},
+	}
+
+	headerB64, errEncode := encodeToBase64(&header)
+	if errEncode != nil {
+		t.Fail()
+		t.Logf(errEncode.Error())
+	}
+
+	decoded, errDecode := decodeFromBase64(headerB64, nil)
+	if errDecode != nil {
+		t.Fail()
+		t.Logf(errDecode.Error())
+	}
+
+	headerDetails, errUnmarshal := unmarshalHeader(decoded, nil)
+	if errUnmarshal != nil {
+		t.Fail()
+		t.Logf(errUnmarshal.Error())
+	}
+
+	if headerDetails.PrivateParams["cty"] != "JWT" {
+		t.Fail()
+		t.Logf("expected cty to round-trip through Header.PrivateParams")
+	}
+}
+
+func TestCreateJWTFromSecretStrictAndValidateJWTStrictRoundTrip(t *testing.T) {
+	params := CreateJWTParams{
+		Aud:      []string{testLocalSessions},
+		Iss:      tmk3,
+		Sub:      testPerson,
+		Lifetime: 3600,
+	}
+	algorithm := HS256([]byte(lazyFox))
+
+	tokenPayload, errToken := CreateJWTFromSecretStrict(&params, algorithm, nil)
+	if errToken != nil {
+		t.Fail()
+		t.Logf(errToken.Error())
+	}
+
+	tokenIsValid, errValidate := ValidateJWTStrict(tokenPayload, algorithm, nil)
+	if !tokenIsValid {
+		t.Fail()
+		t.Logf("expected strict token to validate")
+	}
+	if errValidate != nil {
+		t.Fail()
+		t.Logf(errValidate.Error())
+	}
+
+	tokenDetails, errDetails := RetrieveTokenDetailsStrict(tokenPayload.Token, nil)
+	if errDetails != nil {
+		t.Fail()
+		t.Logf(errDetails.Error())
+	}
+	if tokenDetails.Claims.Sub != testPerson {
+		t.Fail()
+		t.Logf("expected sub to round-trip through RetrieveTokenDetailsStrict")
+	}
+}
+
+func TestCreateJWTStrictProducesBase64URLSegments(t *testing.T) {
+	params := CreateJWTParams{
+		Aud:      []string{testLocalSessions},
+		Iss:      tmk3,
+		Sub:      testPerson,
+		Lifetime: 3600,
+		Custom:   map[string]interface{}{"scope": "read:everything/more+stuff"},
+	}
+
+	tokenPayload, errToken := CreateJWTStrict(&params, nil)
+	if errToken != nil {
+		t.Fail()
+		t.Logf(errToken.Error())
+	}
+
+	for _, chunk := range strings.Split(*tokenPayload.Token, periodRune) {
+		if strings.ContainsAny(chunk, "+/=") {
+			t.Fail()
+			t.Logf("expected base64url segment with no padding or +/ characters, found %q", chunk)
+		}
+
+		if _, errDecode := decodeFromBase64URL(&chunk, nil); errDecode != nil {
+			t.Fail()
+			t.Logf(errDecode.Error())
+		}
+	}
+}
+
+func TestGenerateSecureRandomByteArrayVaries(t *testing.T) {
+	first, errFirst := generateSecureRandomByteArray(randomLength, nil)
+	if errFirst != nil {
+		t.Fail()
+		t.Logf(errFirst.Error())
+	}
+
+	second, errSecond := generateSecureRandomByteArray(randomLength, nil)
+	if errSecond != nil {
+		t.Fail()
+		t.Logf(errSecond.Error())
+	}
+
+	if len(*first) != randomLength || len(*second) != randomLength {
+		t.Fail()
+		t.Logf("expected generated byte arrays of length %d", randomLength)
+	}
+
+	if string(*first) == string(*second) {
+		t.Fail()
+		t.Logf("expected two secure random draws to differ")
+	}
+}
+
+// TestCreateJWTStrictInteropWithGolangJWT feeds a CreateJWTStrict token
+// through github.com/golang-jwt/jwt, a third-party parser, to prove the
+// base64url fix actually buys interoperability and not just round-trip
+// agreement with this package's own decodeFromBase64URL.
+func TestCreateJWTStrictInteropWithGolangJWT(t *testing.T) {
+	params := CreateJWTParams{
+		Aud:      []string{testLocalSessions},
+		Iss:      tmk3,
+		Sub:      testPerson,
+		Lifetime: 3600,
+	}
+
+	tokenPayload, errToken := CreateJWTStrict(&params, nil)
+	if errToken != nil {
+		t.Fail()
+		t.Logf(errToken.Error())
+	}
+
+	parsedClaims := jwt.MapClaims{}
+	parsedToken, errParse := jwt.ParseWithClaims(*tokenPayload.Token, parsedClaims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		return *tokenPayload.Secret, nil
+	})
+	if errParse != nil {
+		t.Fail()
+		t.Logf(errParse.Error())
+	}
+	if parsedToken == nil || !parsedToken.Valid {
+		t.Fail()
+		t.Logf("expected golang-jwt/jwt to accept the strict token as valid")
+	}
+
+	sub, errSub := parsedClaims.GetSubject()
+	if errSub != nil {
+		t.Fail()
+		t.Logf(errSub.Error())
+	}
+	if sub != testPerson {
+		t.Fail()
+		t.Logf("expected sub claim to round-trip through golang-jwt/jwt")
+	}
+}
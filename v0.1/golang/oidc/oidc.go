@@ -0,0 +1,78 @@
+// Package oidc implements the OIDC discovery convenience flow: resolve a
+// JWKS resolver and issuer check straight from an issuer URL.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	jwts "github.com/herebythere/jwts/v0.1/golang/jwts"
+	"github.com/herebythere/jwts/v0.1/golang/jwks"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+var errIssuerMismatch = errors.New("token iss does not match provider issuer")
+
+type discoveryDocument struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// IssuerVerifier validates that a token's iss claim matches the issuer a
+// KeyResolver was discovered from, on top of the usual signature check.
+type IssuerVerifier struct {
+	Issuer   string
+	Resolver jwts.KeyResolver
+}
+
+// VerifyToken checks the token's signature via Resolver, asserts its iss
+// claim equals Issuer, and checks its exp/nbf/iat claims.
+func (v *IssuerVerifier) VerifyToken(token *string) (bool, error) {
+	tokenIsValid, errValidate := jwts.ValidateTokenWithResolver(token, v.Resolver, nil)
+	if errValidate != nil || !tokenIsValid {
+		return false, errValidate
+	}
+
+	tokenDetails, errDetails := jwts.ParseTokenDetails(token, nil)
+	if errDetails != nil {
+		return false, errDetails
+	}
+
+	if tokenDetails.Claims.Iss != v.Issuer {
+		return false, errIssuerMismatch
+	}
+
+	return jwts.VerifyTokenWithPolicy(token, nil, nil)
+}
+
+// NewProvider discovers issuer's jwks_uri via the standard OIDC discovery
+// document and returns a KeyResolver over it plus an IssuerVerifier that
+// additionally checks iss.
+func NewProvider(ctx context.Context, issuer string, opts ...jwks.Option) (jwts.KeyResolver, *IssuerVerifier, error) {
+	discoveryURL := strings.TrimSuffix(issuer, "/") + discoveryPath
+
+	req, errReq := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if errReq != nil {
+		return nil, nil, errReq
+	}
+
+	resp, errDo := http.DefaultClient.Do(req)
+	if errDo != nil {
+		return nil, nil, errDo
+	}
+	defer resp.Body.Close()
+
+	var doc discoveryDocument
+	if errDecode := json.NewDecoder(resp.Body).Decode(&doc); errDecode != nil {
+		return nil, nil, errDecode
+	}
+
+	resolver := jwks.NewRemoteKeySet(doc.JWKSURI, opts...)
+	verifier := &IssuerVerifier{Issuer: doc.Issuer, Resolver: resolver}
+
+	return resolver, verifier, nil
+}
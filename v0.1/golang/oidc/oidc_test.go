@@ -0,0 +1,147 @@
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jwts "github.com/herebythere/jwts/v0.1/golang/jwts"
+	"github.com/herebythere/jwts/v0.1/golang/jwk"
+)
+
+const testIssuer = "tmk3-oidc-test"
+
+func newTestIssuer(t *testing.T, secret []byte) *httptest.Server {
+	mux := http.NewServeMux()
+
+	var issuerURL string
+
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(discoveryDocument{
+			Issuer:  testIssuer,
+			JWKSURI: issuerURL + "/jwks.json",
+		})
+	})
+	mux.HandleFunc("/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(jwk.Set{
+			Keys: []jwk.Key{
+				{Kty: "oct", Kid: "oidc-test-kid", Alg: "HS256", K: base64.RawURLEncoding.EncodeToString(secret)},
+			},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	issuerURL = server.URL
+
+	return server
+}
+
+func TestNewProviderVerifiesIssuerAndSignature(t *testing.T) {
+	secret := []byte("oidc-test-secret")
+	server := newTestIssuer(t, secret)
+	defer server.Close()
+
+	_, verifier, errProvider := NewProvider(context.Background(), server.URL)
+	if errProvider != nil {
+		t.Fail()
+		t.Logf(errProvider.Error())
+	}
+
+	params := jwts.CreateTokenParams{
+		Aud:      []string{"oidc_test"},
+		Iss:      testIssuer,
+		Sub:      "test_person",
+		Lifetime: 3600,
+		KeyID:    "oidc-test-kid",
+	}
+	token, errToken := jwts.CreateToken(&params, jwts.HS256, secret, nil)
+	if errToken != nil {
+		t.Fail()
+		t.Logf(errToken.Error())
+	}
+
+	tokenIsValid, errVerify := verifier.VerifyToken(token)
+	if !tokenIsValid {
+		t.Fail()
+		t.Logf("token should be valid")
+	}
+	if errVerify != nil {
+		t.Fail()
+		t.Logf(errVerify.Error())
+	}
+}
+
+func TestIssuerVerifierRejectsWrongIssuer(t *testing.T) {
+	secret := []byte("oidc-test-secret")
+	server := newTestIssuer(t, secret)
+	defer server.Close()
+
+	_, verifier, errProvider := NewProvider(context.Background(), server.URL)
+	if errProvider != nil {
+		t.Fail()
+		t.Logf(errProvider.Error())
+	}
+
+	params := jwts.CreateTokenParams{
+		Aud:      []string{"oidc_test"},
+		Iss:      "someone-else",
+		Sub:      "test_person",
+		Lifetime: 3600,
+		KeyID:    "oidc-test-kid",
+	}
+	token, errToken := jwts.CreateToken(&params, jwts.HS256, secret, nil)
+	if errToken != nil {
+		t.Fail()
+		t.Logf(errToken.Error())
+	}
+
+	tokenIsValid, errVerify := verifier.VerifyToken(token)
+	if tokenIsValid {
+		t.Fail()
+		t.Logf("token with mismatched iss should not verify")
+	}
+	if errVerify != errIssuerMismatch {
+		t.Fail()
+		t.Logf("expected errIssuerMismatch")
+	}
+}
+
+func TestIssuerVerifierRejectsExpiredToken(t *testing.T) {
+	secret := []byte("oidc-test-secret")
+	server := newTestIssuer(t, secret)
+	defer server.Close()
+
+	_, verifier, errProvider := NewProvider(context.Background(), server.URL)
+	if errProvider != nil {
+		t.Fail()
+		t.Logf(errProvider.Error())
+	}
+
+	params := jwts.CreateTokenParams{
+		Aud:      []string{"oidc_test"},
+		Iss:      testIssuer,
+		Sub:      "test_person",
+		Lifetime: -3600,
+		KeyID:    "oidc-test-kid",
+	}
+	token, errToken := jwts.CreateToken(&params, jwts.HS256, secret, nil)
+	if errToken != nil {
+		t.Fail()
+		t.Logf(errToken.Error())
+	}
+
+	tokenIsValid, errVerify := verifier.VerifyToken(token)
+	if tokenIsValid {
+		t.Fail()
+		t.Logf("expired token should not verify")
+	}
+	if errVerify != jwts.ErrTokenExpired {
+		t.Fail()
+		t.Logf("expected jwts.ErrTokenExpired")
+	}
+}